@@ -0,0 +1,66 @@
+package gofig
+
+import (
+	"sort"
+	"strings"
+)
+
+// addAliases maps the old names of a field to its key, aliases are relative to the parent key and
+// deprecated keys are from the root.
+func (l *Loader) addAliases(parent, key string, tag Tag) {
+	for _, a := range tag.Aliases {
+		old := l.keyFormatter.Format(
+			strings.Trim(strings.Join([]string{parent, a}, l.delimiter), l.delimiter), l.delimiter)
+
+		l.aliases[old] = key
+	}
+
+	for _, d := range tag.Deprecated {
+		l.aliases[l.keyFormatter.Format(d, l.delimiter)] = key
+	}
+}
+
+// alias returns the current key for an old key, keys below an old key are moved below the current
+// key, e.g pool.max.idle becomes db.max_connections.idle.
+func (l *Loader) alias(key string) (string, string, bool) {
+	elms := strings.Split(key, l.delimiter)
+
+	for i := len(elms); i > 0; i-- {
+		old := strings.Join(elms[:i], l.delimiter)
+
+		if k, ok := l.aliases[old]; ok {
+			return strings.Join(append([]string{k}, elms[i:]...), l.delimiter), old, true
+		}
+	}
+
+	return key, "", false
+}
+
+// setAliased sets the values of old keys, warning that they are deprecated. Values of keys also
+// given by their current name by the parser are not set.
+func (l *Loader) setAliased(p PrioritisedParser, values map[string]interface{}, named map[string]bool) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var errs MultiError
+
+	for _, key := range keys {
+		k, old, _ := l.alias(key)
+
+		l.logger.Printf("%s from %s is deprecated, use %s", old, parserName(p), l.aliases[old])
+
+		if named[k] {
+			l.log().Printf("%s not set, %s is also set", key, k)
+
+			continue
+		}
+
+		errs.Add(l.setValue(p, k, values[key]))
+	}
+
+	return errs.NilOrError()
+}
@@ -0,0 +1,214 @@
+package gofig
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A ConverterFunc converts a value from a parser to a type. The converted value is set on the field
+// if it is assignable to the fields type, else it is set like any other value, for example a
+// converter for an int type may return an int64.
+type ConverterFunc func(value interface{}) (interface{}, error)
+
+// standardConverters returns the converters every Loader starts with, see WithConverter.
+func standardConverters() map[reflect.Type]ConverterFunc {
+	return map[reflect.Type]ConverterFunc{
+		reflect.TypeOf(ByteSize(0)):     ByteSizeConverter,
+		reflect.TypeOf(url.URL{}):       URLConverter,
+		reflect.TypeOf(regexp.Regexp{}): RegexpConverter,
+		reflect.TypeOf(net.IPNet{}):     CIDRConverter,
+		reflect.TypeOf(os.FileMode(0)):  FileModeConverter,
+	}
+}
+
+// numberTypes are the types number literal converters are added for, see WithNumberLiterals.
+var numberTypes = map[reflect.Type]ConverterFunc{
+	reflect.TypeOf(int(0)):     IntConverter,
+	reflect.TypeOf(int8(0)):    IntConverter,
+	reflect.TypeOf(int16(0)):   IntConverter,
+	reflect.TypeOf(int32(0)):   IntConverter,
+	reflect.TypeOf(int64(0)):   IntConverter,
+	reflect.TypeOf(uint(0)):    UintConverter,
+	reflect.TypeOf(uint8(0)):   UintConverter,
+	reflect.TypeOf(uint16(0)):  UintConverter,
+	reflect.TypeOf(uint32(0)):  UintConverter,
+	reflect.TypeOf(uint64(0)):  UintConverter,
+	reflect.TypeOf(uintptr(0)): UintConverter,
+}
+
+// convert converts the value with the converter for the fields type. The converted value is
+// returned with true if it has been set on the field.
+func (d decoder) convert(field reflect.Value, value interface{}) (interface{}, bool, error) {
+	fn, ok := d.converters[field.Type()]
+	if !ok {
+		return value, false, nil
+	}
+
+	v, err := fn(value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.IsValid() && rv.Type().AssignableTo(field.Type()) {
+		field.Set(rv)
+
+		return v, true, nil
+	}
+
+	return v, false, nil
+}
+
+// A ByteSize is a number of bytes. Byte sizes can be set from strings with SI or IEC units, e.g
+// 500MB or 512MiB, the units K, M, G, T and P on their own are IEC units.
+type ByteSize uint64
+
+// byteUnits maps lower case units to their size in bytes.
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"k":   1 << 10,
+	"m":   1 << 20,
+	"g":   1 << 30,
+	"t":   1 << 40,
+	"p":   1 << 50,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// ParseByteSize parses a byte size, e.g 512MiB, 1.5GB or 1024.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	unit, ok := byteUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size unit %q", s[i:])
+	}
+
+	b := n * unit
+	if b != math.Trunc(b) || b >= math.MaxUint64 {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	return ByteSize(b), nil
+}
+
+// ByteSizeConverter converts strings to a ByteSize, see ParseByteSize. Numbers are bytes.
+func ByteSizeConverter(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	return ParseByteSize(s)
+}
+
+// URLConverter converts strings to a url.URL.
+func URLConverter(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return *u, nil
+}
+
+// RegexpConverter compiles strings to a regexp.Regexp.
+func RegexpConverter(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return *re, nil
+}
+
+// CIDRConverter converts strings in CIDR notation, e.g 10.0.0.0/8, to a net.IPNet.
+func CIDRConverter(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return *n, nil
+}
+
+// FileModeConverter converts octal strings, e.g 0644, to an os.FileMode. Numbers are used as is,
+// parsers such as YAML already treat numbers with a leading 0 as octal.
+func FileModeConverter(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	m, err := strconv.ParseUint(strings.TrimPrefix(s, "0o"), 8, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.FileMode(m), nil
+}
+
+// IntConverter converts strings written as Go integer literals, e.g 0xff, 0o755, 0b1010 or
+// 1_000_000, to an int64.
+func IntConverter(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(s), 0, 64)
+}
+
+// UintConverter converts strings written as Go integer literals, e.g 0xff, 0o755, 0b1010 or
+// 1_000_000, to an uint64.
+func UintConverter(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(s), 0, 64)
+}
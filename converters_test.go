@@ -0,0 +1,128 @@
+package gofig
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseByteSize(t *testing.T) {
+	cases := map[string]struct {
+		value string
+		want  ByteSize
+		err   bool
+	}{
+		"Bytes":       {value: "1024", want: 1024},
+		"BytesUnit":   {value: "10B", want: 10},
+		"SI":          {value: "500MB", want: 500e6},
+		"IEC":         {value: "512MiB", want: 512 << 20},
+		"Short":       {value: "2G", want: 2 << 30},
+		"Fraction":    {value: "1.5 KiB", want: 1536},
+		"LowerCase":   {value: "1kb", want: 1000},
+		"Partial":     {value: "1.5B", err: true},
+		"InvalidUnit": {value: "1XB", err: true},
+		"Empty":       {value: "", err: true},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseByteSize(tc.value)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("want error, got: %v", got)
+				}
+
+				t.Log(err)
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestConverters(t *testing.T) {
+	type Level int
+
+	type Config struct {
+		Size   ByteSize       `gofig:"size"`
+		URL    url.URL        `gofig:"url"`
+		URLPtr *url.URL       `gofig:"url_ptr"`
+		Match  *regexp.Regexp `gofig:"match"`
+		CIDR   net.IPNet      `gofig:"cidr"`
+		Mode   os.FileMode    `gofig:"mode"`
+		Level  Level          `gofig:"level"`
+		Mask   uint8          `gofig:"mask"`
+		Count  int            `gofig:"count"`
+		Files  []os.FileMode  `gofig:"files"`
+	}
+
+	p := NewInMemoryParser()
+	p.Add("size", "512MiB")
+	p.Add("url", "https://example.com/path")
+	p.Add("url_ptr", "http://localhost:8080")
+	p.Add("match", "^foo.*$")
+	p.Add("cidr", "10.0.0.0/8")
+	p.Add("mode", "0644")
+	p.Add("level", "high")
+	p.Add("mask", "0xff")
+	p.Add("count", "1_000")
+	p.Add("files", []interface{}{"0755", 420})
+
+	var cfg Config
+
+	g, err := New(&cfg,
+		WithNumberLiterals(),
+		WithConverter(reflect.TypeOf(Level(0)), func(v interface{}) (interface{}, error) {
+			if v == "high" {
+				return Level(2), nil
+			}
+
+			return v, nil
+		}))
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if err := g.Parse(p); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	want := Config{
+		Size:   512 << 20,
+		URL:    url.URL{Scheme: "https", Host: "example.com", Path: "/path"},
+		URLPtr: &url.URL{Scheme: "http", Host: "localhost:8080"},
+		Match:  regexp.MustCompile("^foo.*$"),
+		CIDR:   net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)},
+		Mode:   0644,
+		Level:  2,
+		Mask:   0xff,
+		Count:  1000,
+		Files:  []os.FileMode{0755, 0644},
+	}
+
+	// Regular expressions are compared by their source
+	re := cmp.Comparer(func(a, b *regexp.Regexp) bool {
+		return a.String() == b.String()
+	})
+
+	if !cmp.Equal(want, cfg, re) {
+		t.Errorf("\nwant: %+v\ngot:  %+v", want, cfg)
+	}
+}
@@ -1,6 +1,7 @@
 package gofig
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -40,16 +41,165 @@ func (e ErrSetValue) Error() string {
 	)
 }
 
-// CloseError is returned by Close when one or more notifiers error on their Close.
-type CloseError struct {
+// ErrArrayLength is returned when a list of values does not match the length of an array.
+type ErrArrayLength struct {
+	Len int
+	Got int
+}
+
+func (e ErrArrayLength) Error() string {
+	return fmt.Sprintf("array of length %d cannot be set with %d values", e.Len, e.Got)
+}
+
+// ErrValidation is returned when a field fails validation. Parser is the parser that set the
+// fields value, this is nil for struct level validation.
+type ErrValidation struct {
+	Key    string
+	Parser Parser
+	Err    error
+}
+
+func (e ErrValidation) Error() string {
+	msg := fmt.Sprintf("invalid %s: %s", e.Key, e.Err)
+	if e.Key == "" {
+		msg = fmt.Sprintf("invalid configuration: %s", e.Err)
+	}
+
+	if e.Parser != nil {
+		msg = fmt.Sprintf("%s (set by %s)", msg, parserName(e.Parser))
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying validation error.
+func (e ErrValidation) Unwrap() error {
+	return e.Err
+}
+
+// ErrVetoed is returned when a BeforeApply hook vetoes a reload.
+type ErrVetoed struct {
+	Err error
+}
+
+func (e ErrVetoed) Error() string {
+	return fmt.Sprintf("reload vetoed: %s", e.Err)
+}
+
+// Unwrap returns the error the hook vetoed the reload with.
+func (e ErrVetoed) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnknownKey is returned in strict mode when a parser sets a key that does not match any field.
+// Suggestion is the closest known key, if any key is close enough to be a likely misspelling.
+type ErrUnknownKey struct {
+	Key        string
+	Parser     Parser
+	Suggestion string
+}
+
+func (e ErrUnknownKey) Error() string {
+	msg := fmt.Sprintf("unknown key %s", e.Key)
+
+	if e.Parser != nil {
+		msg = fmt.Sprintf("%s from %s", msg, parserName(e.Parser))
+	}
+
+	if e.Suggestion != "" {
+		msg = fmt.Sprintf("%s, did you mean %s?", msg, e.Suggestion)
+	}
+
+	return msg
+}
+
+// ErrMissingKeys is returned by CheckRequired when one or more required keys have not been set.
+type ErrMissingKeys struct {
+	Keys []MissingKey
+}
+
+func (e ErrMissingKeys) Error() string {
+	points := make([]string, len(e.Keys))
+	for i, k := range e.Keys {
+		points[i] = fmt.Sprintf("* %s", k)
+	}
+
+	return fmt.Sprintf(
+		"%d required keys not set:\n\t%s\n\n",
+		len(e.Keys), strings.Join(points, "\n\t"))
+}
+
+// A MissingKey is a required key no parser set. Names holds the source specific names parsers
+// looked for the key by, e.g environment variable names.
+type MissingKey struct {
+	Key   string
+	Names []string
+}
+
+func (k MissingKey) String() string {
+	if len(k.Names) == 0 {
+		return k.Key
+	}
+
+	return fmt.Sprintf("%s (%s)", k.Key, strings.Join(k.Names, ", "))
+}
+
+// FieldError is returned when a parsers value cannot be set on a field. File and Line locate the
+// value within the parsers source when known, see Locator.
+type FieldError struct {
+	Key    string
+	Parser Parser
+	File   string
+	Line   int
+	Err    error
+}
+
+func (e FieldError) Error() string {
+	msg := fmt.Sprintf("invalid value for %s", e.Key)
+
+	if src := e.source(); src != "" {
+		msg = fmt.Sprintf("%s from %s", msg, src)
+	}
+
+	return fmt.Sprintf("%s: %s", msg, e.Err)
+}
+
+// source returns the file and line or the name of the parser the value came from.
+func (e FieldError) source() string {
+	switch {
+	case e.File != "" && e.Line > 0:
+		return fmt.Sprintf("%s:%d", e.File, e.Line)
+	case e.File != "":
+		return e.File
+	case e.Parser != nil && e.Line > 0:
+		return fmt.Sprintf("%s:%d", parserName(e.Parser), e.Line)
+	case e.Parser != nil:
+		return parserName(e.Parser)
+	}
+
+	return ""
+}
+
+// Unwrap returns the underlying error.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError holds one or more errors, for example every error from a single Parse. errors.Is and
+// errors.As match any of the held errors.
+type MultiError struct {
 	errors []error
 }
 
-func (e *CloseError) Error() string {
-	if e == nil || e.errors == nil {
+func (e *MultiError) Error() string {
+	if e == nil || len(e.errors) == 0 {
 		return "no errors"
 	}
 
+	if len(e.errors) == 1 {
+		return e.errors[0].Error()
+	}
+
 	points := make([]string, len(e.errors))
 	for i, err := range e.errors {
 		points[i] = fmt.Sprintf("* %s", err)
@@ -60,28 +210,62 @@ func (e *CloseError) Error() string {
 		len(e.errors), strings.Join(points, "\n\t"))
 }
 
-// Add adds one or more errors to the error group.
-func (e *CloseError) Add(errs ...error) {
+// Add adds one or more errors to the error group. Nil errors are ignored and the errors of a
+// MultiError are added individually.
+func (e *MultiError) Add(errs ...error) {
+	for _, err := range errs {
+		switch t := err.(type) {
+		case nil:
+			continue
+		case *MultiError:
+			if t != nil {
+				e.errors = append(e.errors, t.errors...)
+			}
+		default:
+			e.errors = append(e.errors, err)
+		}
+	}
+}
+
+// Errors returns the held errors.
+func (e *MultiError) Errors() []error {
 	if e == nil {
-		*e = *new(CloseError)
+		return nil
 	}
 
-	if e.errors == nil {
-		e.errors = make([]error, len(errs))
+	return e.errors
+}
+
+// Is returns true if any of the held errors match the target, see errors.Is.
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Errors() {
+		if errors.Is(err, target) {
+			return true
+		}
 	}
 
-	e.errors = append(e.errors, errs...)
+	return false
 }
 
-// NilOrError returns an error if there are errors, else nil.
-func (e *CloseError) NilOrError() error {
-	if e == nil {
-		return nil
+// As finds the first held error matching the target, see errors.As.
+func (e *MultiError) As(target interface{}) bool {
+	for _, err := range e.Errors() {
+		if errors.As(err, target) {
+			return true
+		}
 	}
 
-	if e.errors == nil || len(e.errors) == 0 {
+	return false
+}
+
+// NilOrError returns an error if there are errors, else nil.
+func (e *MultiError) NilOrError() error {
+	if e == nil || len(e.errors) == 0 {
 		return nil
 	}
 
 	return e
 }
+
+// CloseError is returned by Close when one or more notifiers error on their Close.
+type CloseError = MultiError
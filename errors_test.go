@@ -0,0 +1,77 @@
+package gofig
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMultiError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	var inner MultiError
+	inner.Add(errBoom, nil)
+
+	var errs MultiError
+	errs.Add(nil, &inner, FieldError{Key: "foo", Err: errors.New("bad")})
+
+	if got := len(errs.Errors()); got != 2 {
+		t.Fatalf("want 2 errors, got %d: %v", got, errs.Errors())
+	}
+
+	err := errs.NilOrError()
+
+	if !errors.Is(err, errBoom) {
+		t.Error("want errors.Is to match boom")
+	}
+
+	var fe FieldError
+	if !errors.As(err, &fe) || fe.Key != "foo" {
+		t.Errorf("want FieldError for foo, got: %v", fe)
+	}
+
+	var verr ErrValidation
+	if errors.As(err, &verr) {
+		t.Error("want errors.As not to match ErrValidation")
+	}
+
+	var empty MultiError
+	if err := empty.NilOrError(); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	cases := map[string]struct {
+		err  FieldError
+		want string
+	}{
+		"Key": {
+			err:  FieldError{Key: "db.port", Err: errors.New("bad")},
+			want: "invalid value for db.port: bad",
+		},
+		"Parser": {
+			err:  FieldError{Key: "db.port", Parser: NewInMemoryParser(), Err: errors.New("bad")},
+			want: "invalid value for db.port from memory: bad",
+		},
+		"File": {
+			err:  FieldError{Key: "db.port", File: "config.yaml", Err: errors.New("bad")},
+			want: "invalid value for db.port from config.yaml: bad",
+		},
+		"Line": {
+			err:  FieldError{Key: "db.port", File: "config.yaml", Line: 12, Err: errors.New("bad")},
+			want: "invalid value for db.port from config.yaml:12: bad",
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.err.Error(); got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package gofig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A Candidate is a value a parser offered for a field.
+type Candidate struct {
+	Parser   Parser
+	Priority int
+	Value    interface{}
+}
+
+func (c Candidate) String() string {
+	return fmt.Sprintf("%v from %s (priority %d)", c.Value, parserName(c.Parser), c.Priority)
+}
+
+// An Explanation describes where a keys value came from. Parser is the parser that set the value
+// and Candidates holds every value offered for the key, highest priority first.
+type Explanation struct {
+	Key        string
+	Value      interface{}
+	Parser     Parser
+	Candidates []Candidate
+}
+
+func (e Explanation) String() string {
+	if e.Parser == nil {
+		return fmt.Sprintf("%s = %v (not set)", e.Key, e.Value)
+	}
+
+	points := make([]string, len(e.Candidates))
+	for i, c := range e.Candidates {
+		mark := " "
+		if c.Parser == e.Parser {
+			mark = "*"
+		}
+
+		points[i] = fmt.Sprintf("%s %s", mark, c)
+	}
+
+	return fmt.Sprintf("%s = %v set by %s:\n\t%s",
+		e.Key, e.Value, parserName(e.Parser), strings.Join(points, "\n\t"))
+}
+
+// Explain returns an Explanation of where the value for the given key came from. False is
+// returned if the key is unknown.
+func (l *Loader) Explain(key string) (Explanation, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key = l.keyFormatter.Format(key, l.delimiter)
+
+	field, ok := l.fields[key]
+	if !ok {
+		return Explanation{}, false
+	}
+
+	candidates := append([]Candidate(nil), field.Candidates()...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Priority > candidates[j].Priority
+	})
+
+	return Explanation{
+		Key:        key,
+		Value:      deepCopy(field.Value(), make(map[uintptr]reflect.Value)).Interface(),
+		Parser:     unwrap(field.Parser()),
+		Candidates: candidates,
+	}, true
+}
+
+// IsSet returns true if the given key has been set by a parser, including defaults. For maps this
+// returns true if any key within the map has been set.
+func (l *Loader) IsSet(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	field, ok := l.fields[l.keyFormatter.Format(key, l.delimiter)]
+	if !ok {
+		return false
+	}
+
+	return l.isSet(field)
+}
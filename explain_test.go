@@ -0,0 +1,67 @@
+package gofig
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExplain(t *testing.T) {
+	type Config struct {
+		Timeout string            `gofig:"timeout,default=1s"`
+		Name    string            `gofig:"name"`
+		Map     map[string]string `gofig:"map"`
+	}
+
+	var cfg Config
+
+	g, err := New(&cfg, SetLogger(NopLogger()))
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	p1 := NewInMemoryParser()
+	p1.Add("timeout", "5s")
+	p1.Add("map.foo", "bar")
+
+	p2 := NewInMemoryParser()
+	p2.Add("timeout", "10s")
+
+	if err := g.Parse(p1, p2); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	e, ok := g.Explain("timeout")
+	if !ok {
+		t.Fatal("want explanation for timeout")
+	}
+
+	t.Log(e)
+
+	if e.Parser != p2 || len(e.Candidates) < 2 || e.Candidates[1].Parser != p1 {
+		t.Errorf("want timeout set by p2 over p1, got %s", e)
+	}
+
+	want := []interface{}{"10s", "5s", "1s"}
+	got := make([]interface{}, len(e.Candidates))
+
+	for i, c := range e.Candidates {
+		got[i] = c.Value
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("\nwant: %+v\ngot:  %+v", want, got)
+	}
+
+	for key, want := range map[string]bool{
+		"timeout": true,
+		"name":    false,
+		"map":     true,
+		"map.foo": true,
+		"unknown": false,
+	} {
+		if got := g.IsSet(key); got != want {
+			t.Errorf("want IsSet(%s) %t, got %t", key, want, got)
+		}
+	}
+}
@@ -1,8 +1,13 @@
 package gofig
 
 import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 // A Field represents a type that we can set a value for based on its key.
@@ -17,6 +22,19 @@ type Field interface {
 	CanSet(Prioritiser) bool
 	// SetPriority sets the fields priority.
 	SetPriority(Prioritiser)
+	// SetParser records the parser that set the fields value.
+	SetParser(PrioritisedParser)
+	// Parser returns the parser that set the fields value, nil if the value has not been set.
+	Parser() PrioritisedParser
+	// AddCandidate records a value offered by a parser, replacing any previous offer from the same
+	// parser.
+	AddCandidate(Candidate)
+	// Candidates returns the values offered by each parser.
+	Candidates() []Candidate
+	// IsSet returns true once the fields value has been set.
+	IsSet() bool
+	// Tag returns the fields struct tag.
+	Tag() Tag
 }
 
 // Fields holds a map of keys to fields.
@@ -29,20 +47,34 @@ func (f Fields) Set(k string, v Field) {
 
 // A Field holds the fields struct path and reflected value.
 type field struct {
-	key      string // foo.bar.baz
-	value    reflect.Value
-	priority uint8
+	key        string // foo.bar.baz
+	value      reflect.Value
+	tag        Tag
+	priority   int
+	parser     PrioritisedParser
+	candidates []Candidate
+	set        bool
+	alloc      func() // allocates nil struct pointers the field is within
+	decoder    decoder
 }
 
-func newField(k string, v reflect.Value) *field {
+func newField(k string, v reflect.Value, t Tag) *field {
 	return &field{
-		key:   k,
-		value: v,
+		key:     k,
+		value:   v,
+		tag:     t,
+		decoder: decoder{tag: t, key: k},
 	}
 }
 
 func (f *field) Set(value interface{}) error {
-	return set(f.value, value)
+	if err := f.decoder.set(f.value, value); err != nil {
+		return err
+	}
+
+	f.set = true
+
+	return nil
 }
 
 func (f *field) Key() string {
@@ -61,6 +93,84 @@ func (f *field) SetPriority(p Prioritiser) {
 	f.priority = p.Priority()
 }
 
+// A stateful field returns the field holding its state.
+type stateful interface {
+	state() *field
+}
+
+func (f *field) state() *field {
+	return f
+}
+
+// adopt takes the state of another field, the parsers and priority that set it and its candidates.
+func (f *field) adopt(from *field) {
+	f.priority = from.priority
+	f.parser = from.parser
+	f.candidates = from.candidates
+	f.set = from.set
+}
+
+// allocator returns the function allocating the struct pointers the field is within.
+func (f *field) allocator() func() {
+	return f.alloc
+}
+
+func (f *field) SetParser(p PrioritisedParser) {
+	f.parser = p
+}
+
+func (f *field) Parser() PrioritisedParser {
+	return f.parser
+}
+
+func (f *field) AddCandidate(c Candidate) {
+	for i, fc := range f.candidates {
+		if fc.Parser == c.Parser {
+			f.candidates[i] = c
+
+			return
+		}
+	}
+
+	f.candidates = append(f.candidates, c)
+}
+
+func (f *field) Candidates() []Candidate {
+	return f.candidates
+}
+
+func (f *field) IsSet() bool {
+	return f.set
+}
+
+func (f *field) Tag() Tag {
+	return f.tag
+}
+
+// setDecoder sets the decoder used to set the fields value, the fields tag options are kept.
+func (f *field) setDecoder(d decoder) {
+	d.tag = f.tag
+	d.key = f.key
+	f.decoder = d
+}
+
+// setSource records the parser setting the fields value.
+func (f *field) setSource(p Parser) {
+	f.decoder.parser = p
+}
+
+// A decodable field decodes values with the Loaders decoding options.
+type decodable interface {
+	setDecoder(decoder)
+	setSource(Parser)
+}
+
+// An allocator allocates the struct pointers a field is within. This is called once a field has been
+// set by any parser other than defaults, so defaults alone do not allocate struct pointers.
+type allocator interface {
+	allocator() func()
+}
+
 // mapField embedded field wrapping map key values allowing setting map fields to be the same as
 // setting struct fields.
 type mapField struct {
@@ -72,7 +182,7 @@ type mapField struct {
 
 func newMapField(k, mk string, mp reflect.Value) *mapField {
 	return &mapField{
-		field: newField(k, reflect.New(mp.Type().Elem()).Elem()),
+		field: newField(k, reflect.New(mp.Type().Elem()).Elem(), Tag{}),
 
 		mk: reflect.ValueOf(mk),
 		mp: mp,
@@ -91,14 +201,346 @@ func (f *mapField) Set(v interface{}) error {
 	return nil
 }
 
-func set(field reflect.Value, value interface{}) error {
-	if u := unmarshaler(field); u != nil {
+// pointerField is a pointer to a struct. The pointer remains nil until a field within the struct
+// is set, until then those fields are set on the shadow value. Setting a nil value sets the pointer
+// to nil.
+type pointerField struct {
+	*field
+
+	shadow reflect.Value // the value the pointer will point to
+}
+
+func newPointerField(k string, v reflect.Value, t Tag, alloc func()) *pointerField {
+	shadow := v
+	if v.IsNil() {
+		shadow = reflect.New(v.Type().Elem())
+	}
+
+	f := &pointerField{
+		field:  newField(k, v, t),
+		shadow: shadow,
+	}
+
+	f.field.alloc = alloc
+
+	return f
+}
+
+func (f *pointerField) Set(v interface{}) error {
+	if v == nil {
+		f.shadow.Elem().Set(reflect.Zero(f.shadow.Elem().Type()))
+
+		return f.field.Set(nil)
+	}
+
+	if err := f.decoder.set(f.shadow.Elem(), v); err != nil {
+		return err
+	}
+
+	f.alloc()
+	f.set = true
+
+	return nil
+}
+
+// allocator returns nil, struct pointers allocate themselves on Set.
+func (f *pointerField) allocator() func() {
+	return nil
+}
+
+// alloc points the pointer at the shadow value, then allocates any parent pointers. The pointer is
+// set first as parents such as slice elements copy the value they hold when allocated.
+func (f *pointerField) alloc() {
+	if f.value.IsNil() {
+		f.value.Set(f.shadow)
+	}
+
+	if f.field.alloc != nil {
+		f.field.alloc()
+	}
+}
+
+// sliceField is a slice of structs. Each element is held separately so fields within them can be
+// set by index, e.g servers.0.host, the elements are then written back into the slice. Lists are
+// set element by element by the Loader, setting the field itself only empties the slice.
+type sliceField struct {
+	*field
+
+	elems []reflect.Value // pointers to each element
+}
+
+func newSliceField(k string, v reflect.Value, t Tag, alloc func()) *sliceField {
+	f := &sliceField{
+		field: newField(k, v, t),
+	}
+
+	f.field.alloc = alloc
+
+	return f
+}
+
+func (f *sliceField) Set(v interface{}) error {
+	if v == nil {
+		f.elems = nil
+
+		return f.field.Set(nil)
+	}
+
+	if k := reflect.ValueOf(v).Kind(); k != reflect.Slice && k != reflect.Array {
+		return ErrSetValue{
+			Field: f.value,
+			Value: reflect.ValueOf(v),
+		}
+	}
+
+	f.elems = make([]reflect.Value, 0)
+	f.set = true
+
+	return nil
+}
+
+// allocator returns commit, writing the elements back into the slice.
+func (f *sliceField) allocator() func() {
+	return f.commit
+}
+
+// elemType returns the struct type of the slices elements.
+func (f *sliceField) elemType() reflect.Type {
+	t := f.value.Type().Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// newElem adds a new element, copying the existing value at index i if there is one.
+func (f *sliceField) newElem(i int) reflect.Value {
+	ev := reflect.New(f.elemType())
+
+	if i < f.value.Len() {
+		v := f.value.Index(i)
+		if v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+
+		if v.Kind() == reflect.Struct {
+			ev.Elem().Set(v)
+		}
+	}
+
+	f.elems = append(f.elems, ev)
+
+	return ev
+}
+
+// commit writes the elements back into the slice, allocating struct pointers the slice is within.
+func (f *sliceField) commit() {
+	if f.elems == nil {
+		f.value.Set(reflect.Zero(f.value.Type()))
+	} else {
+		s := reflect.MakeSlice(f.value.Type(), len(f.elems), len(f.elems))
+
+		for i, ev := range f.elems {
+			if f.value.Type().Elem().Kind() == reflect.Ptr {
+				s.Index(i).Set(ev)
+			} else {
+				s.Index(i).Set(ev.Elem())
+			}
+		}
+
+		f.value.Set(s)
+	}
+
+	if f.field.alloc != nil {
+		f.field.alloc()
+	}
+}
+
+// structMapField is a map of structs. Like sliceField each element is held separately so fields
+// within them can be set by map key, e.g backends.eu.timeout, the elements are then written back
+// into the map. Setting the field itself to nil removes all of the elements.
+type structMapField struct {
+	*field
+
+	elems map[string]reflect.Value // pointers to each element
+}
+
+func newStructMapField(k string, v reflect.Value, t Tag, alloc func()) *structMapField {
+	f := &structMapField{
+		field: newField(k, v, t),
+		elems: make(map[string]reflect.Value),
+	}
+
+	f.field.alloc = alloc
+
+	return f
+}
+
+func (f *structMapField) Set(v interface{}) error {
+	if v != nil {
+		return ErrSetValue{
+			Field: f.value,
+			Value: reflect.ValueOf(v),
+		}
+	}
+
+	f.elems = make(map[string]reflect.Value)
+
+	return f.field.Set(nil)
+}
+
+// elemType returns the struct type of the maps elements.
+func (f *structMapField) elemType() reflect.Type {
+	t := f.value.Type().Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// newElem adds a new element for the map key, copying the existing value if there is one.
+func (f *structMapField) newElem(mk string) reflect.Value {
+	ev := reflect.New(f.elemType())
+
+	if !f.value.IsNil() {
+		v := f.value.MapIndex(f.mapKey(mk))
+		if v.IsValid() && v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+
+		if v.IsValid() && v.Kind() == reflect.Struct {
+			ev.Elem().Set(v)
+		}
+	}
+
+	f.elems[mk] = ev
+
+	return ev
+}
+
+// mapKey converts the key to the maps key type.
+func (f *structMapField) mapKey(mk string) reflect.Value {
+	return reflect.ValueOf(mk).Convert(f.value.Type().Key())
+}
+
+// commit returns a function writing the element for the map key back into the map, allocating
+// struct pointers the map is within.
+func (f *structMapField) commit(mk string) func() {
+	return func() {
+		ev, ok := f.elems[mk]
+		if !ok {
+			return
+		}
+
+		if f.value.IsNil() {
+			f.value.Set(reflect.MakeMap(f.value.Type()))
+		}
+
+		if f.value.Type().Elem().Kind() == reflect.Ptr {
+			f.value.SetMapIndex(f.mapKey(mk), ev)
+		} else {
+			f.value.SetMapIndex(f.mapKey(mk), ev.Elem())
+		}
+
+		if f.field.alloc != nil {
+			f.field.alloc()
+		}
+	}
+}
+
+// A decoder sets values on fields, converting them to the fields type. The fields tag options, such
+// as the unit of a duration, apply to the field and any elements within it.
+type decoder struct {
+	tag        Tag
+	converters map[reflect.Type]ConverterFunc
+	json       bool     // fall back to json.Unmarshaler
+	opts       []Option // the Loaders options, used to decode structs for ContextUnmarshalers
+	key        string   // the key of the field being set
+	parser     Parser   // the parser setting the value
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// isLeaf returns true for types that are set as a single value rather than by their fields or map
+// keys, such as time.Time, types with a converter and types implementing Unmarshaler,
+// encoding.TextUnmarshaler, or json.Unmarshaler if enabled.
+func (d decoder) isLeaf(t reflect.Type) bool {
+	if _, ok := d.converters[t]; ok {
+		return true
+	}
+
+	if t == timeType || t == locationPtrType.Elem() {
+		return true
+	}
+
+	if implements(t, unmarshalerType) || implements(t, contextUnmarshalerType) ||
+		implements(t, textUnmarshalerType) {
+		return true
+	}
+
+	return d.json && implements(t, jsonUnmarshalerType)
+}
+
+// implements returns true if the type or a pointer to it implements the interface.
+func implements(t reflect.Type, iface reflect.Type) bool {
+	return t.Implements(iface) || (t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(iface))
+}
+
+func (d decoder) set(field reflect.Value, value interface{}) error {
+	// A nil value, such as null in YAML or JSON, sets the zero value
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+
+		return nil
+	}
+
+	value, ok, err := d.convert(field, value)
+	if err != nil || ok {
+		return err
+	}
+
+	switch u := unmarshaler(field).(type) {
+	case ContextUnmarshaler:
+		return u.UnmarshalGoFigContext(UnmarshalContext{
+			Key:     d.key,
+			Parser:  d.parser,
+			decoder: decoder{converters: d.converters, json: d.json, opts: d.opts},
+		}, value)
+	case Unmarshaler:
 		return u.UnmarshalGoFig(value)
 	}
 
+	switch field.Type() {
+	case durationType:
+		return d.setDuration(field, value)
+	case timeType:
+		return d.setTime(field, value)
+	case locationPtrType:
+		return setLocation(field, value)
+	}
+
+	if s, ok := value.(string); ok {
+		if u, ok := addrInterface(field, textUnmarshalerType).(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+
+	if d.json {
+		if u, ok := addrInterface(field, jsonUnmarshalerType).(json.Unmarshaler); ok {
+			return setJSON(u, value)
+		}
+	}
+
 	switch field.Kind() {
 	case reflect.Ptr:
-		return set(field.Elem(), value)
+		return d.setPtr(field, value)
+	case reflect.Bool:
+		return setBool(field, value)
 	case reflect.String:
 		return setString(field, value)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -107,8 +549,16 @@ func set(field reflect.Value, value interface{}) error {
 		return setUint(field, value)
 	case reflect.Float32, reflect.Float64:
 		return setFloat(field, value)
-	case reflect.Slice, reflect.Array:
-		return setSlice(field, value)
+	case reflect.Complex64, reflect.Complex128:
+		return setComplex(field, value)
+	case reflect.Slice:
+		return d.setSlice(field, value)
+	case reflect.Array:
+		return d.setArray(field, value)
+	case reflect.Map:
+		return d.setMap(field, value)
+	case reflect.Interface:
+		return setInterface(field, value)
 	}
 
 	return ErrInvalidConversion{
@@ -117,6 +567,193 @@ func set(field reflect.Value, value interface{}) error {
 	}
 }
 
+// addrInterface returns the address of the field as an interface{} if it implements the interface,
+// else nil. Pointer fields are allocated by setPtr before their element is set.
+func addrInterface(field reflect.Value, iface reflect.Type) interface{} {
+	if field.Kind() == reflect.Ptr || !field.CanAddr() {
+		return nil
+	}
+
+	v := field.Addr()
+	if !v.Type().Implements(iface) || !v.CanInterface() {
+		return nil
+	}
+
+	return v.Interface()
+}
+
+// setJSON sets the value with its json.Unmarshaler by encoding the value as JSON.
+func setJSON(u json.Unmarshaler, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return u.UnmarshalJSON(b)
+}
+
+// setInterface sets the raw value on empty interface fields, see plain.
+func setInterface(field reflect.Value, value interface{}) error {
+	if field.NumMethod() > 0 {
+		return ErrInvalidConversion{
+			To:   field.Kind(),
+			From: reflect.ValueOf(value).Kind(),
+		}
+	}
+
+	field.Set(reflect.ValueOf(plain(value)))
+
+	return nil
+}
+
+// plain returns the value with any json.Number within it, as the JSON parser returns numbers,
+// converted to an int64, or a float64 if it is not a whole number. Dynamic fields then hold the
+// same types whichever parser set them.
+func plain(value interface{}) interface{} {
+	switch t := value.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			m[k] = plain(v)
+		}
+
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, v := range t {
+			s[i] = plain(v)
+		}
+
+		return s
+	}
+
+	return value
+}
+
+// setMap replaces the fields value with a map of the given values converted to the maps element
+// type.
+func (d decoder) setMap(field reflect.Value, value interface{}) error {
+	ft := field.Type()
+
+	m, ok := toMap(value)
+	if !ok || ft.Key().Kind() != reflect.String {
+		return ErrSetValue{
+			Field: field,
+			Value: reflect.ValueOf(value),
+		}
+	}
+
+	mv := reflect.MakeMapWithSize(ft, len(m))
+
+	for k, v := range m {
+		e := reflect.New(ft.Elem()).Elem()
+		if err := d.set(e, v); err != nil {
+			return err
+		}
+
+		mv.SetMapIndex(reflect.ValueOf(k).Convert(ft.Key()), e)
+	}
+
+	field.Set(mv)
+
+	return nil
+}
+
+// subtreeField sets a value within the raw subtree held by an interface{} or
+// map[string]interface{} field, e.g plugins.foo.bar sets plugins["foo"]["bar"].
+type subtreeField struct {
+	*field
+
+	root reflect.Value // the interface{} or map[string]interface{} field
+	path []string      // path to the value within the subtree
+}
+
+func newSubtreeField(k string, root reflect.Value, path []string) *subtreeField {
+	return &subtreeField{
+		field: newField(k, reflect.New(interfaceType).Elem(), Tag{}),
+
+		root: root,
+		path: path,
+	}
+}
+
+func (f *subtreeField) Set(v interface{}) error {
+	var m map[string]interface{}
+
+	switch f.root.Kind() {
+	case reflect.Map:
+		if f.root.IsNil() {
+			f.root.Set(reflect.MakeMap(f.root.Type()))
+		}
+
+		m = f.root.Convert(reflect.TypeOf(m)).Interface().(map[string]interface{})
+	default:
+		cur, ok := f.root.Interface().(map[string]interface{})
+		if !ok {
+			cur = make(map[string]interface{})
+			f.root.Set(reflect.ValueOf(cur))
+		}
+
+		m = cur
+	}
+
+	setPath(m, f.path, plain(v))
+
+	return f.field.Set(v)
+}
+
+// setPath sets the value within the nested map at the given path, e.g foo.bar sets
+// m["foo"]["bar"].
+func setPath(m map[string]interface{}, path []string, v interface{}) {
+	for _, k := range path[:len(path)-1] {
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[k] = next
+		}
+
+		m = next
+	}
+
+	m[path[len(path)-1]] = v
+}
+
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// isDynamic returns true for empty interface and map[string]interface{} types which hold the raw
+// values from parsers.
+func isDynamic(t reflect.Type) bool {
+	if t.Kind() == reflect.Map && t.Key().Kind() == reflect.String {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Interface && t.NumMethod() == 0
+}
+
+// setPtr sets the value the pointer points to, allocating the pointer if it is nil.
+func (d decoder) setPtr(field reflect.Value, value interface{}) error {
+	if !field.IsNil() {
+		return d.set(field.Elem(), value)
+	}
+
+	v := reflect.New(field.Type().Elem())
+	if err := d.set(v.Elem(), value); err != nil {
+		return err
+	}
+
+	field.Set(v)
+
+	return nil
+}
+
 // setString sets the fields value to a string.
 func setString(field reflect.Value, value interface{}) error {
 	v, ok := value.(string)
@@ -132,13 +769,79 @@ func setString(field reflect.Value, value interface{}) error {
 	return nil
 }
 
-// setInt sets the fields value to an integer.
+// setBool sets the fields value to a bool. Strings such as true, 1, yes and on are true and
+// false, 0, no and off are false.
+func setBool(field reflect.Value, value interface{}) error {
+	var b bool
+
+	switch t := value.(type) {
+	case bool:
+		b = t
+	case string:
+		v, err := parseBool(t)
+		if err != nil {
+			return err
+		}
+
+		b = v
+	case int, int8, int16, int32, int64:
+		v := reflect.ValueOf(t).Int()
+		if v != 0 && v != 1 {
+			return ErrSetValue{
+				Field: field,
+				Value: reflect.ValueOf(value),
+			}
+		}
+
+		b = v == 1
+	case json.Number:
+		v, err := parseBool(t.String())
+		if err != nil {
+			return ErrSetValue{
+				Field: field,
+				Value: reflect.ValueOf(value),
+			}
+		}
+
+		b = v
+	default:
+		return ErrSetValue{
+			Field: field,
+			Value: reflect.ValueOf(value),
+		}
+	}
+
+	field.SetBool(b)
+
+	return nil
+}
+
+// parseBool parses a bool from a string, extending strconv.ParseBool with yes, no, on and off.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	}
+
+	return strconv.ParseBool(strings.TrimSpace(s))
+}
+
+// setInt sets the fields value to an integer. Floats must be whole numbers.
 func setInt(field reflect.Value, value interface{}) error {
 	var i int64
 
 	switch t := value.(type) {
 	case string:
-		v, err := strconv.ParseInt(t, 10, 64)
+		v, err := parseInt(t)
+		if err != nil {
+			return err
+		}
+
+		i = v
+	case json.Number:
+		v, err := parseInt(t.String())
 		if err != nil {
 			return err
 		}
@@ -153,11 +856,37 @@ func setInt(field reflect.Value, value interface{}) error {
 	case int32:
 		i = int64(t)
 	case int64:
-		i = int64(t)
+		i = t
+	case uint, uint8, uint16, uint32, uint64:
+		v := reflect.ValueOf(t).Uint()
+		if v > math.MaxInt64 {
+			return ErrSetValue{
+				Field: field,
+				Value: reflect.ValueOf(value),
+			}
+		}
+
+		i = int64(v)
 	case float32:
-		i = int64(t)
+		v, ok := floatToInt(float64(t))
+		if !ok {
+			return ErrSetValue{
+				Field: field,
+				Value: reflect.ValueOf(value),
+			}
+		}
+
+		i = v
 	case float64:
-		i = int64(t)
+		v, ok := floatToInt(t)
+		if !ok {
+			return ErrSetValue{
+				Field: field,
+				Value: reflect.ValueOf(value),
+			}
+		}
+
+		i = v
 	default:
 		return ErrSetValue{
 			Field: field,
@@ -177,32 +906,79 @@ func setInt(field reflect.Value, value interface{}) error {
 	return nil
 }
 
-// setUint64 sets the fields value to an unsigned integer type.
+// parseInt parses an integer from a string. Numbers such as 1e3 or 1.0 are accepted as long as
+// they are whole numbers.
+func parseInt(s string) (int64, error) {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		return i, nil
+	}
+
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil {
+		return 0, err
+	}
+
+	i, ok := floatToInt(f)
+	if !ok {
+		return 0, err
+	}
+
+	return i, nil
+}
+
+// floatToInt converts a float to an integer, false is returned if the float is not a whole number
+// or is out of range.
+func floatToInt(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+
+	return int64(f), true
+}
+
+// setUint64 sets the fields value to an unsigned integer type. Negative numbers and floats that
+// are not whole numbers are rejected.
 func setUint(field reflect.Value, value interface{}) error {
 	var i uint64
 
 	switch t := value.(type) {
 	case string:
-		v, err := strconv.ParseUint(t, 10, 64)
+		v, err := parseUint(t)
 		if err != nil {
 			return err
 		}
 
 		i = v
-	case int:
-		i = uint64(t)
-	case int8:
-		i = uint64(t)
-	case int16:
-		i = uint64(t)
-	case int32:
-		i = uint64(t)
-	case int64:
-		i = uint64(t)
-	case float32:
-		i = uint64(t)
-	case float64:
-		i = uint64(t)
+	case json.Number:
+		v, err := parseUint(t.String())
+		if err != nil {
+			return err
+		}
+
+		i = v
+	case int, int8, int16, int32, int64:
+		v := reflect.ValueOf(t).Int()
+		if v < 0 {
+			return ErrSetValue{
+				Field: field,
+				Value: reflect.ValueOf(value),
+			}
+		}
+
+		i = uint64(v)
+	case uint, uint8, uint16, uint32, uint64:
+		i = reflect.ValueOf(t).Uint()
+	case float32, float64:
+		v := reflect.ValueOf(t).Float()
+		if v != math.Trunc(v) || v < 0 || v >= math.MaxUint64 {
+			return ErrSetValue{
+				Field: field,
+				Value: reflect.ValueOf(value),
+			}
+		}
+
+		i = uint64(v)
 	default:
 		return ErrSetValue{
 			Field: field,
@@ -222,6 +998,22 @@ func setUint(field reflect.Value, value interface{}) error {
 	return nil
 }
 
+// parseUint parses an unsigned integer from a string. Like parseInt whole numbers such as 1e3 are
+// accepted.
+func parseUint(s string) (uint64, error) {
+	i, err := strconv.ParseUint(s, 10, 64)
+	if err == nil {
+		return i, nil
+	}
+
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil || f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+		return 0, err
+	}
+
+	return uint64(f), nil
+}
+
 // setFloat sets the fields value to the a float.
 func setFloat(field reflect.Value, value interface{}) error {
 	var i float64
@@ -233,11 +1025,22 @@ func setFloat(field reflect.Value, value interface{}) error {
 			return err
 		}
 
+		i = v
+	case json.Number:
+		v, err := t.Float64()
+		if err != nil {
+			return err
+		}
+
 		i = v
 	case float32:
 		i = float64(t)
 	case float64:
-		i = float64(t)
+		i = t
+	case int, int8, int16, int32, int64:
+		i = float64(reflect.ValueOf(t).Int())
+	case uint, uint8, uint16, uint32, uint64:
+		i = float64(reflect.ValueOf(t).Uint())
 	default:
 		return ErrSetValue{
 			Field: field,
@@ -257,29 +1060,123 @@ func setFloat(field reflect.Value, value interface{}) error {
 	return nil
 }
 
-// setSlice sets the fields value to the given slice.
-func setSlice(field reflect.Value, value interface{}) error {
-	ft := field.Type()
+// setComplex sets the fields value to a complex number. Strings are in the form 1+2i.
+func setComplex(field reflect.Value, value interface{}) error {
+	var c complex128
+
+	switch t := value.(type) {
+	case string:
+		if _, err := fmt.Sscan(t, &c); err != nil {
+			return err
+		}
+	case json.Number:
+		v, err := t.Float64()
+		if err != nil {
+			return err
+		}
+
+		c = complex(v, 0)
+	case complex64:
+		c = complex128(t)
+	case complex128:
+		c = t
+	case int, int8, int16, int32, int64:
+		c = complex(float64(reflect.ValueOf(t).Int()), 0)
+	case uint, uint8, uint16, uint32, uint64:
+		c = complex(float64(reflect.ValueOf(t).Uint()), 0)
+	case float32, float64:
+		c = complex(reflect.ValueOf(t).Float(), 0)
+	default:
+		return ErrSetValue{
+			Field: field,
+			Value: reflect.ValueOf(value),
+		}
+	}
+
+	if field.OverflowComplex(c) {
+		return ErrSetValue{
+			Field: field,
+			Value: reflect.ValueOf(c),
+		}
+	}
+
+	field.SetComplex(c)
+
+	return nil
+}
+
+// list returns the value as a reflected slice or array. Strings are treated as comma separated
+// lists, e.g 1,2,3.
+func list(field reflect.Value, value interface{}) (reflect.Value, error) {
+	if s, ok := value.(string); ok {
+		elms := make([]string, 0)
+
+		if s != "" {
+			for _, e := range strings.Split(s, ",") {
+				elms = append(elms, strings.TrimSpace(e))
+			}
+		}
+
+		value = elms
+	}
+
 	vv := reflect.ValueOf(value)
 
 	if vv.Kind() != reflect.Array && vv.Kind() != reflect.Slice {
-		return ErrInvalidValue{
-			Type: ft,
+		return reflect.Value{}, ErrInvalidValue{
+			Type: field.Type(),
 		}
 	}
 
-	s := reflect.MakeSlice(reflect.SliceOf(ft.Elem()), vv.Len(), vv.Cap())
+	return vv, nil
+}
+
+// setSlice sets the fields value to the given slice. Strings are treated as comma separated
+// lists, e.g 1,2,3.
+func (d decoder) setSlice(field reflect.Value, value interface{}) error {
+	ft := field.Type()
+
+	vv, err := list(field, value)
+	if err != nil {
+		return err
+	}
+
+	s := reflect.MakeSlice(ft, vv.Len(), vv.Len())
 
 	for i := 0; i < vv.Len(); i++ {
-		e := reflect.New(ft.Elem())
-		if err := set(e, vv.Index(i).Interface()); err != nil {
+		if err := d.set(s.Index(i), vv.Index(i).Interface()); err != nil {
 			return err
 		}
-
-		s.Index(i).Set(e.Elem())
 	}
 
 	field.Set(s)
 
 	return nil
 }
+
+// setArray sets the fields value to the given list, the list must be the same length as the array.
+func (d decoder) setArray(field reflect.Value, value interface{}) error {
+	vv, err := list(field, value)
+	if err != nil {
+		return err
+	}
+
+	if vv.Len() != field.Len() {
+		return ErrArrayLength{
+			Len: field.Len(),
+			Got: vv.Len(),
+		}
+	}
+
+	a := reflect.New(field.Type()).Elem()
+
+	for i := 0; i < vv.Len(); i++ {
+		if err := d.set(a.Index(i), vv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	field.Set(a)
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package gofig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type level int
+
+func (l *level) UnmarshalText(b []byte) error {
+	for i, v := range []string{"debug", "info", "warn"} {
+		if v == string(b) {
+			*l = level(i)
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown level %s", b)
+}
+
+type point struct {
+	X, Y int
+}
+
+func (p *point) UnmarshalJSON(b []byte) error {
+	var v [2]int
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	p.X, p.Y = v[0], v[1]
+
+	return nil
+}
+
+func TestSet(t *testing.T) {
+	cases := map[string]struct {
+		dst   interface{}
+		value interface{}
+		tag   Tag
+		json  bool
+		want  interface{}
+		err   bool
+	}{
+		"Bool":             {dst: new(bool), value: true, want: true},
+		"BoolString":       {dst: new(bool), value: "true", want: true},
+		"BoolStringOne":    {dst: new(bool), value: "1", want: true},
+		"BoolStringYes":    {dst: new(bool), value: "Yes", want: true},
+		"BoolStringOff":    {dst: new(bool), value: "off", want: false},
+		"BoolInt":          {dst: new(bool), value: 1, want: true},
+		"BoolInvalid":      {dst: new(bool), value: "maybe", err: true},
+		"BoolJSONNumber":   {dst: new(bool), value: json.Number("1"), want: true},
+		"BoolJSONInvalid":  {dst: new(bool), value: json.Number("2"), err: true},
+		"IntFloat":         {dst: new(int), value: float64(2), want: 2},
+		"IntFloatFraction": {dst: new(int), value: 1.9, err: true},
+		"IntUint":          {dst: new(int), value: uint(2), want: 2},
+		"IntOverflow":      {dst: new(int8), value: 128, err: true},
+		"IntJSONNumber":    {dst: new(int64), value: json.Number("9007199254740993"), want: int64(9007199254740993)},
+		"IntJSONExponent":  {dst: new(int), value: json.Number("1e3"), want: 1000},
+		"IntJSONFraction":  {dst: new(int), value: json.Number("1.5"), err: true},
+		"UintNegative":     {dst: new(uint), value: -1, err: true},
+		"UintJSONNumber":   {dst: new(uint64), value: json.Number("18446744073709551615"), want: uint64(18446744073709551615)},
+		"FloatInt":         {dst: new(float64), value: 2, want: float64(2)},
+		"FloatJSONNumber":  {dst: new(float64), value: json.Number("1.5"), want: 1.5},
+		"Complex":          {dst: new(complex128), value: complex(1, 2), want: complex(1, 2)},
+		"ComplexString":    {dst: new(complex128), value: "1+2i", want: complex(1, 2)},
+		"ComplexFloat":     {dst: new(complex64), value: 1.5, want: complex64(complex(1.5, 0))},
+		"Array":            {dst: new([3]int), value: []interface{}{1, 2, 3}, want: [3]int{1, 2, 3}},
+		"ArrayString":      {dst: new([2]string), value: "a, b", want: [2]string{"a", "b"}},
+		"ArrayLength":      {dst: new([4]int), value: []int{1, 2, 3}, err: true},
+		"SliceBool":        {dst: new([]bool), value: "true,no", want: []bool{true, false}},
+		"Duration":         {dst: new(time.Duration), value: "1m30s", want: 90 * time.Second},
+		"DurationInt":      {dst: new(time.Duration), value: 5, want: time.Duration(5)},
+		"DurationUnit":     {dst: new(time.Duration), value: 30, tag: Tag{Unit: "s"}, want: 30 * time.Second},
+		"DurationUnitStr":  {dst: new(time.Duration), value: "1.5", tag: Tag{Unit: "m"}, want: 90 * time.Second},
+		"DurationInvalid":  {dst: new(time.Duration), value: "soon", err: true},
+		"DurationBadUnit":  {dst: new(time.Duration), value: 1, tag: Tag{Unit: "weeks"}, err: true},
+		"DurationSlice":    {dst: new([]time.Duration), value: "1s,2", tag: Tag{Unit: "s"}, want: []time.Duration{time.Second, 2 * time.Second}},
+		"Time":             {dst: new(time.Time), value: "2020-04-01T12:30:00Z", want: time.Date(2020, 4, 1, 12, 30, 0, 0, time.UTC)},
+		"TimeValue":        {dst: new(time.Time), value: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)},
+		"TimeLayout":       {dst: new(time.Time), value: "2020-04-01", tag: Tag{Layout: "2006-01-02"}, want: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)},
+		"TimeInvalid":      {dst: new(time.Time), value: "yesterday", err: true},
+		"Location":         {dst: new(*time.Location), value: "UTC", want: time.UTC},
+		"LocationInvalid":  {dst: new(*time.Location), value: "Nowhere/Special", err: true},
+		"TextIP":           {dst: new(net.IP), value: "127.0.0.1", want: net.ParseIP("127.0.0.1")},
+		"TextLevel":        {dst: new(level), value: "warn", want: level(2)},
+		"TextLevelInt":     {dst: new(level), value: 1, want: level(1)},
+		"TextLevelPtr":     {dst: new(*level), value: "warn", want: func() *level { l := level(2); return &l }()},
+		"TextInvalid":      {dst: new(level), value: "loud", err: true},
+		"JSONPoint":        {dst: new(point), value: []interface{}{1, 2}, json: true, want: point{X: 1, Y: 2}},
+		"JSONDisabled":     {dst: new(point), value: []interface{}{1, 2}, err: true},
+		"InterfaceJSON":    {dst: new(interface{}), value: json.Number("9007199254740993"), want: int64(9007199254740993)},
+		"InterfaceFloat":   {dst: new(interface{}), value: json.Number("1.5"), want: 1.5},
+		"MapJSONNumbers": {
+			dst:   new(map[string]interface{}),
+			value: map[string]interface{}{"n": json.Number("1"), "l": []interface{}{json.Number("0.5")}},
+			want:  map[string]interface{}{"n": int64(1), "l": []interface{}{0.5}},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dst := reflect.ValueOf(tc.dst).Elem()
+
+			err := decoder{tag: tc.tag, json: tc.json}.set(dst, tc.value)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("want error, got: %v", dst.Interface())
+				}
+
+				t.Log(err)
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			// Locations are compared by name
+			loc := cmp.Comparer(func(a, b *time.Location) bool {
+				return a.String() == b.String()
+			})
+
+			if !cmp.Equal(tc.want, dst.Interface(), loc) {
+				t.Errorf("want %v, got %v", tc.want, dst.Interface())
+			}
+		})
+	}
+}
+
+func TestSetArrayLength(t *testing.T) {
+	var a [2]int
+
+	err := decoder{}.set(reflect.ValueOf(&a).Elem(), []int{1})
+
+	var lerr ErrArrayLength
+	if !errors.As(err, &lerr) {
+		t.Fatalf("want ErrArrayLength, got: %v", err)
+	}
+
+	if lerr.Len != 2 || lerr.Got != 1 {
+		t.Errorf("want length 2 got 1, got length %d got %d", lerr.Len, lerr.Got)
+	}
+}
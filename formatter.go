@@ -36,3 +36,18 @@ func CaseInsensitiveKeys() Formatter {
 		return strings.Join(elm, delimiter)
 	})
 }
+
+// NormalisedKeys returns a Formatter that formats keys to snake case so keys written in different
+// styles resolve to the same field, e.g maxConns, max-conns, max_conns and MaxConns are all
+// formatted to max_conns. Like field names, acronyms are kept together, see SnakeCase.
+func NormalisedKeys() Formatter {
+	return FormatterFunc(func(key string, delimiter string) string {
+		elm := strings.Split(key, delimiter)
+
+		for i, k := range elm {
+			elm[i] = strings.ToLower(strings.Join(words(k), "_"))
+		}
+
+		return strings.Join(elm, delimiter)
+	})
+}
@@ -2,8 +2,11 @@ package gofig
 
 import (
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // Gofig default configuration.
@@ -11,11 +14,34 @@ const (
 	DefaultStructTag = "gofig"
 )
 
-// Loader parses configuration from one or more sources.
+// Loader parses configuration from one or more sources. A Loader is safe to use from multiple
+// goroutines, read the configuration from Current rather than the struct given to New while
+// parsers may change it.
 type Loader struct {
+	// guards the loader and the struct being configured
+	mu sync.Mutex
+
+	// snapshots of the configuration, see Current and Watch
+	current  atomic.Value
+	watchers map[chan interface{}]chan struct{} // closed to stop watching
+
 	// parsers priority mapping
 	parsers Parsers
 
+	// parsers in the order they were first parsed, reloads parse every one of them again
+	parsed []PrioritisedParser
+
+	// the values each parser last returned, reused by reloads for parsers that are not Reloadable
+	last map[Parser][]keyValue
+
+	// the struct being configured and a copy of its values before any parser set them
+	dst     reflect.Value
+	initial reflect.Value
+
+	// default values from struct tags, the lowest priority parser
+	defaults       *InMemoryParser
+	defaultsParser PrioritisedParser
+
 	// notifiers we are currently watching
 	notifiers []NotifyParser
 	wg        sync.WaitGroup
@@ -23,15 +49,42 @@ type Loader struct {
 	// flattened map of field keys to struct reflect values
 	fields Fields
 
+	// structs implementing the Validator interface
+	validators []structValidator
+
+	// decoding options applied to every field
+	decoder decoder
+
+	// values below leaf fields, collected while parsing and set once the parser is done
+	leaves map[string]map[string]interface{}
+
+	// old keys of fields tagged with alias or deprecated, mapped to the fields key
+	aliases map[string]string
+
+	// true while fields are flattened again from a committed reload, see commit
+	rebinding bool
+
 	// Configurable options
-	keyFormatter    Formatter // case sensitive
-	structTag       string    // gofig
-	enforcePriority bool      // true
-	delimiter       string    // "."
+	keyFormatter    Formatter      // case sensitive
+	namingStrategy  NamingStrategy // field names
+	structTags      []string       // gofig
+	enforcePriority bool           // true
+	omitEmpty       bool           // false
+	delimiter       string         // "."
+
+	// hooks that can veto reloads
+	beforeApply []BeforeApply
+
+	// Strict mode, unknown keys from every parser or the given parsers are errors
+	strictAll     bool
+	strictParsers map[Parser]bool
 
 	// Logging configuration
 	logger Logger
 	debug  bool
+
+	// options the loader was constructed with
+	opts []Option
 }
 
 // New constructs a new Loader
@@ -47,39 +100,149 @@ func New(dst interface{}, opts ...Option) (*Loader, error) {
 		return nil, ErrInvalidValue{reflect.TypeOf(v)}
 	}
 
+	l := newLoader(opts...)
+
+	l.dst = v.Elem()
+	l.initial = deepCopy(v.Elem(), make(map[uintptr]reflect.Value))
+
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+
+	l.publish()
+
+	return l, nil
+}
+
+// load flattens the struct being configured and applies its default values.
+func (l *Loader) load() error {
+	l.addValidator(l.dst, "")
+	l.flatten(l.dst, l.dst.Type(), "", nil)
+
+	// Apply default values before any other parser, with a priority of 0 any parser can override them.
+	return l.parse(l.defaultsParser)
+}
+
+// newLoader constructs a new Loader applying the given options.
+func newLoader(opts ...Option) *Loader {
 	l := &Loader{
 		parsers:   make(Parsers),
+		defaults:  NewInMemoryParser(),
 		notifiers: make([]NotifyParser, 0),
 		fields:    make(Fields),
+		aliases:   make(map[string]string),
+		last:      make(map[Parser][]keyValue),
+		watchers:  make(map[chan interface{}]chan struct{}),
 
 		// Defaults
 		keyFormatter:    CaseSensitiveKeys(),
-		structTag:       DefaultStructTag,
+		namingStrategy:  FieldNames(),
+		structTags:      []string{DefaultStructTag},
 		enforcePriority: true,
 		delimiter:       ".",
+		decoder: decoder{
+			converters: standardConverters(),
+		},
 
 		// Logger
 		logger: DefaultLogger(),
+
+		opts: opts,
 	}
 
 	for _, opt := range opts {
 		opt.apply(l)
 	}
 
-	l.flatten(v.Elem(), t.Elem(), "")
+	l.defaultsParser = PrioritiseParser(defaultsParser{l.defaults})
+	l.decoder.opts = opts
 
-	return l, nil
+	return l
 }
 
-// Parse parses the given parsers in order. If any one parser fails an error will be returned.
+// Parse parses the given parsers, the configuration is recomputed from them and the values every
+// parser parsed before last returned. Once parsed the configuration is validated, see Validator.
+// Like Reload parsing is transactional, if a parser fails, a value cannot be set or validation
+// fails the current configuration is kept and the given parsers are not added. Every error from
+// parsing and validation is returned together as a MultiError, values that cannot be set are
+// returned as FieldErrors.
 func (l *Loader) Parse(parsers ...Parser) error {
-	for _, p := range parsers {
-		if err := l.parse(l.parsers.Add(p)); err != nil {
-			return err
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.reload(false, parsers...)
+}
+
+// CheckRequired returns an ErrMissingKeys error listing every required key that has not been set
+// by any parser, including defaults. Call this after Parse.
+func (l *Loader) CheckRequired() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var missing []MissingKey
+
+	for key, field := range l.fields {
+		if !field.Tag().Required || l.isSet(field) || l.withinNil(key) {
+			continue
+		}
+
+		mk := MissingKey{
+			Key: key,
+		}
+
+		for p := range l.parsers {
+			if n, ok := p.(KeyNamer); ok {
+				mk.Names = append(mk.Names, n.KeyName(key))
+			}
 		}
+
+		sort.Strings(mk.Names)
+
+		missing = append(missing, mk)
 	}
 
-	return nil
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].Key < missing[j].Key
+	})
+
+	return ErrMissingKeys{
+		Keys: missing,
+	}
+}
+
+// withinNil returns true if the key is within a struct pointer that is nil, optional sections that
+// are not given are not missing their required keys.
+func (l *Loader) withinNil(key string) bool {
+	elms := strings.Split(key, l.delimiter)
+
+	for i := 1; i < len(elms); i++ {
+		if pf, ok := l.fields[strings.Join(elms[:i], l.delimiter)].(*pointerField); ok && pf.value.IsNil() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSet returns true if the field, or for maps and struct pointers any key below it, has been set.
+func (l *Loader) isSet(field Field) bool {
+	if field.IsSet() {
+		return true
+	}
+
+	prefix := field.Key() + l.delimiter
+
+	for key, f := range l.fields {
+		if strings.HasPrefix(key, prefix) && f.IsSet() {
+			return true
+		}
+	}
+
+	return false
 }
 
 // log returns a logger if debug is true
@@ -91,54 +254,258 @@ func (l *Loader) log() Logger {
 	return NopLogger()
 }
 
-// parse parses an single parser.
+// parse parses an single parser, every value is set even if setting another fails.
 func (l *Loader) parse(p PrioritisedParser) error {
+	values, err := l.values(p)
+	if err != nil {
+		return err
+	}
+
+	l.last[p] = values
+
+	return l.apply(p, values)
+}
+
+// A keyValue is a key value pair received from a parser.
+type keyValue struct {
+	key   string
+	value interface{}
+}
+
+// values receives every key value pair from the parser.
+func (l *Loader) values(p PrioritisedParser) ([]keyValue, error) {
 	// Set the delimiter
 	p.SetDelimeter(l.delimiter)
 
 	// Send keys to the parser
 	if err := l.sendKeys(p); err != nil {
-		return nil
+		return nil, err
 	}
 
 	// Get the 	values
 	ch, err := p.Values()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Range over the channel until it's closed processing the returned key / values
+	var values []keyValue
+
+	// Range over the channel until it's closed collecting the returned key / values
 	for fn := range ch {
 		// Call the function passed on the channel returning key value pair
 		key, val := fn()
-		key = l.keyFormatter.Format(key, l.delimiter)
 
-		// Lookup the field
-		field, ok := l.lookup(key)
-		if !ok {
-			l.log().Printf("%s key not found", key)
+		values = append(values, keyValue{key: key, value: val})
+	}
+
+	return values, nil
+}
+
+// apply sets the values received from the parser.
+func (l *Loader) apply(p PrioritisedParser, values []keyValue) error {
+	l.leaves = make(map[string]map[string]interface{})
+
+	var errs MultiError
+
+	// Values of old keys are set once every value is received so current keys win
+	var (
+		aliased = make(map[string]interface{})
+		named   = make(map[string]bool)
+	)
+
+	for _, kv := range values {
+		key := l.keyFormatter.Format(kv.key, l.delimiter)
+
+		if _, _, ok := l.alias(key); ok {
+			aliased[key] = kv.value
+
 			continue
 		}
 
-		// Check we can set the fields value if we are enforcing priority.
-		if l.enforcePriority && !field.CanSet(p) {
-			continue
+		named[key] = true
+
+		errs.Add(l.setValue(p, key, kv.value))
+	}
+
+	errs.Add(l.setAliased(p, aliased, named))
+
+	errs.Add(l.setLeaves(p))
+
+	return errs.NilOrError()
+}
+
+// setLeaves sets the values collected below leaf fields, each leaf receives the whole map of
+// values below its key.
+func (l *Loader) setLeaves(p PrioritisedParser) error {
+	keys := make([]string, 0, len(l.leaves))
+	for key := range l.leaves {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var errs MultiError
+
+	for _, key := range keys {
+		errs.Add(l.setValue(p, key, l.leaves[key]))
+	}
+
+	return errs.NilOrError()
+}
+
+// setValue sets a keys value from the given parser. Maps of values for structs and maps are set
+// key by key and lists for slices of structs are set by index, e.g servers.0.host.
+func (l *Loader) setValue(p PrioritisedParser, key string, val interface{}) error {
+	// Lookup the field
+	field, ok := l.lookup(key)
+	if !ok {
+		// Maps of values for struct sections, e.g db or servers.0.tls, are set key by key
+		if m, ok := toMap(val); ok && l.isStructPath(key) {
+			return l.expand(p, key, m)
 		}
 
-		// Set the value on the field.
-		if err := field.Set(val); err != nil {
-			return err
+		l.log().Printf("%s key not found", key)
+
+		if l.strict(p) {
+			return ErrUnknownKey{
+				Key:        key,
+				Parser:     unwrap(p),
+				Suggestion: l.suggest(key),
+			}
 		}
 
-		// If enforcing we the priority on the field.
-		if l.enforcePriority {
-			field.SetPriority(p)
+		return nil
+	}
+
+	// Keys below leaf fields are collected, the leaf is set once the parser is done
+	if field.Key() != key {
+		path := strings.Split(strings.TrimPrefix(key, field.Key()+l.delimiter), l.delimiter)
+
+		m, ok := l.leaves[field.Key()]
+		if !ok {
+			m = make(map[string]interface{})
+			l.leaves[field.Key()] = m
 		}
+
+		setPath(m, path, val)
+
+		return nil
+	}
+
+	if m, ok := toMap(val); ok && l.expandable(field) {
+		return l.expand(p, key, m)
+	}
+
+	// Record the offered value, even if it does not win.
+	field.AddCandidate(Candidate{
+		Parser:   unwrap(p),
+		Priority: p.Priority(),
+		Value:    val,
+	})
+
+	// Empty values are never set, so they neither replace a value set by another parser nor stop a
+	// lower priority parser setting one, see WithOmitEmpty.
+	if (l.omitEmpty || field.Tag().OmitEmpty) && isEmpty(val) {
+		l.log().Printf("%s empty value omitted", key)
+
+		return nil
+	}
+
+	// Check we can set the fields value if we are enforcing priority.
+	if l.enforcePriority && !field.CanSet(p) {
+		return nil
+	}
+
+	// Setting slices and maps of structs replaces all of their elements.
+	_, isSlice := field.(*sliceField)
+	if _, isMap := field.(*structMapField); isSlice || isMap {
+		l.removeElems(field.Key())
+	}
+
+	// Set the value on the field.
+	if d, ok := field.(decodable); ok {
+		d.setSource(unwrap(p))
+	}
+
+	if err := field.Set(val); err != nil {
+		return l.fieldError(p, key, err)
+	}
+
+	field.SetParser(p)
+
+	// Allocate struct pointers the field is within, defaults alone do not allocate them.
+	if a, ok := field.(allocator); ok && p != l.defaultsParser {
+		if alloc := a.allocator(); alloc != nil {
+			alloc()
+		}
+	}
+
+	// If enforcing we the priority on the field.
+	if l.enforcePriority {
+		field.SetPriority(p)
+	}
+
+	// Set each element of the list by its index.
+	if isSlice && val != nil {
+		items := reflect.ValueOf(val)
+
+		var errs MultiError
+
+		for i := 0; i < items.Len(); i++ {
+			k := strings.Join([]string{key, strconv.Itoa(i)}, l.delimiter)
+
+			errs.Add(l.setValue(p, k, items.Index(i).Interface()))
+		}
+
+		return errs.NilOrError()
 	}
 
 	return nil
 }
 
+// expand sets a map of values below the key key by key.
+func (l *Loader) expand(p PrioritisedParser, key string, m map[string]interface{}) error {
+	var errs MultiError
+
+	for k, v := range m {
+		k = l.keyFormatter.Format(strings.Join([]string{key, k}, l.delimiter), l.delimiter)
+
+		errs.Add(l.setValue(p, k, v))
+	}
+
+	return errs.NilOrError()
+}
+
+// isStructPath returns true if the key is the path of a struct section holding fields, e.g db for
+// the field db.host.
+func (l *Loader) isStructPath(key string) bool {
+	prefix := key + l.delimiter
+
+	for k := range l.fields {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldError returns a FieldError for a value the parser could not set on the key, locating the
+// value within the parsers source if the parser is a Locator.
+func (l *Loader) fieldError(p PrioritisedParser, key string, err error) FieldError {
+	fe := FieldError{
+		Key:    key,
+		Parser: unwrap(p),
+		Err:    err,
+	}
+
+	if lp, ok := unwrap(p).(Locator); ok {
+		fe.File, fe.Line = lp.Locate(key)
+	}
+
+	return fe
+}
+
 // sends keys to the parser.
 func (l *Loader) sendKeys(p Parser) error {
 	// Send the keys
@@ -146,15 +513,25 @@ func (l *Loader) sendKeys(p Parser) error {
 	keyCh := make(chan string, len(l.fields))
 
 	go func() {
-		close(errCh)
+		defer close(errCh)
 
 		if err := p.Keys(keyCh); err != nil {
 			errCh <- err
 		}
 	}()
 
+	for old := range l.aliases {
+		keyCh <- old
+	}
+
 	for _, f := range l.fields {
 		keyCh <- f.Key()
+
+		if sf, ok := f.(*sliceField); ok {
+			for _, k := range l.elementKeys(sf, make(map[reflect.Type]bool)) {
+				keyCh <- k
+			}
+		}
 	}
 
 	close(keyCh)
@@ -171,11 +548,75 @@ func (l *Loader) lookup(key string) (Field, bool) {
 		return nil, false
 	}
 
-	// Return the field if it is not a map
-	if field.Value().Kind() != reflect.Map {
+	// Return the field if it is an exact match
+	if field.Key() == key {
+		return field, true
+	}
+
+	// Slices of structs hold elements by index, e.g servers.0.host
+	if sf, ok := field.(*sliceField); ok {
+		idx := strings.Split(strings.TrimPrefix(key, field.Key()+l.delimiter), l.delimiter)[0]
+
+		i, err := strconv.Atoi(idx)
+		if err != nil || i < 0 {
+			return nil, false
+		}
+
+		if err := l.elem(sf, i); err != nil {
+			l.log().Printf("%s element %d: %s", sf.Key(), i, err)
+
+			return nil, false
+		}
+
+		return l.lookup(key)
+	}
+
+	// Maps of structs hold elements by map key, e.g backends.eu.timeout
+	if mf, ok := field.(*structMapField); ok {
+		mk := strings.Split(strings.TrimPrefix(key, field.Key()+l.delimiter), l.delimiter)[0]
+
+		if err := l.mapElem(mf, mk); err != nil {
+			l.log().Printf("%s element %s: %s", mf.Key(), mk, err)
+
+			return nil, false
+		}
+
+		return l.lookup(key)
+	}
+
+	// Leaf fields receive the whole subtree below them, see setValue
+	if l.decoder.isLeaf(field.Value().Type()) {
 		return field, true
 	}
 
+	// Dynamic fields hold the raw subtree below them, e.g plugins.foo.bar
+	if isDynamic(field.Value().Type()) {
+		root := field.Value()
+		path := strings.Split(strings.TrimPrefix(key, field.Key()+l.delimiter), l.delimiter)
+
+		// Keys below another subtree key belong to the same subtree
+		if parent, ok := field.(*subtreeField); ok {
+			root = parent.root
+			path = append(append([]string(nil), parent.path...), path...)
+		}
+
+		sf := newSubtreeField(key, root, path)
+
+		// Subtree fields within nil struct pointers must allocate them too
+		if a, ok := field.(allocator); ok {
+			sf.alloc = a.allocator()
+		}
+
+		l.addField(key, sf)
+
+		return sf, true
+	}
+
+	// Only maps can hold keys below them
+	if field.Value().Kind() != reflect.Map {
+		return nil, false
+	}
+
 	// The field is a map, this could be a leaf node, init the map
 	// Generate the map key path by removing the root key from the field key
 	// e.g foo.bar.baz becomes baz where bar is a map ahd baz the map key
@@ -189,10 +630,17 @@ func (l *Loader) lookup(key string) (Field, bool) {
 
 	// Make a field we can set map index values on
 	kp := strings.Split(mk, l.delimiter)
-	field = newMapField(key, kp[len(kp)-1], mv)
+	mf := newMapField(key, kp[len(kp)-1], mv)
+
+	// Map fields within nil struct pointers must allocate them too
+	if a, ok := field.(allocator); ok {
+		mf.alloc = a.allocator()
+	}
+
+	field = mf
 
 	// Insert the field into the field map so we don't have to initMap again for this value
-	l.fields.Set(key, field)
+	l.addField(key, field)
 
 	return field, true
 }
@@ -267,16 +715,34 @@ func (l *Loader) initMap(elem reflect.Value, key string) (reflect.Value, error)
 	return elem, nil
 }
 
-// flatten recursively flattens a struct.
-func (l *Loader) flatten(rv reflect.Value, rt reflect.Type, key string) {
+// flatten recursively flattens a struct. Squashed structs, including embedded structs without a
+// tag name, have their fields promoted to the parents key level. Like Go, promoted fields never
+// shadow the parents own fields.
+// Struct pointers are optional, they remain nil until a field within them is set, alloc allocates
+// the pointers the struct is within.
+func (l *Loader) flatten(rv reflect.Value, rt reflect.Type, key string, alloc func()) {
+	var squashed []int
+
 	for i := 0; i < rv.NumField(); i++ {
 		fv := rv.Field(i)
 		ft := rt.Field(i)
+		tag := TagFromStructField(ft, l.structTags...)
 
-		// TODO: embedded support
-		if fv.CanSet() {
-			tag := TagFromStructField(ft, l.structTag)
+		if tag.Ignore {
+			continue
+		}
+
+		if !tag.Named {
+			tag.Name = l.namingStrategy.Name(ft.Name)
+		}
 
+		if tag.Squash && (l.decoder.isStruct(ft.Type) || l.decoder.isStructPtr(ft.Type)) {
+			squashed = append(squashed, i)
+
+			continue
+		}
+
+		if fv.CanSet() {
 			fk := l.keyFormatter.Format(
 				strings.Trim(
 					strings.Join(
@@ -285,12 +751,307 @@ func (l *Loader) flatten(rv reflect.Value, rt reflect.Type, key string) {
 
 			l.log().Printf("<Field %s kind:%s key:%s tag:%s>", ft.Name, fv.Kind(), fk, tag)
 
-			switch fv.Kind() {
-			case reflect.Struct:
-				l.flatten(fv, ft.Type, fk)
+			l.addAliases(key, fk, tag)
+
+			switch {
+			case l.decoder.isStruct(ft.Type):
+				l.addValidator(fv, fk)
+				l.flatten(fv, ft.Type, fk, alloc)
+			case l.decoder.isStructSlice(ft.Type):
+				sf := newSliceField(fk, fv, tag, alloc)
+
+				l.addField(fk, sf)
+
+				for i := 0; i < fv.Len(); i++ {
+					if err := l.elem(sf, i); err != nil {
+						l.log().Printf("%s element %d: %s", fk, i, err)
+					}
+				}
+			case l.decoder.isStructMap(ft.Type):
+				mf := newStructMapField(fk, fv, tag, alloc)
+
+				l.addField(fk, mf)
+
+				for _, mk := range fv.MapKeys() {
+					if err := l.mapElem(mf, mk.String()); err != nil {
+						l.log().Printf("%s element %s: %s", fk, mk, err)
+					}
+				}
+			case l.decoder.isStructPtr(ft.Type):
+				pf := newPointerField(fk, fv, tag, alloc)
+
+				l.addField(fk, pf)
+				l.addValidator(fv, fk)
+				l.flatten(pf.shadow.Elem(), ft.Type.Elem(), fk, pf.alloc)
 			default:
-				l.fields.Set(fk, newField(fk, fv))
+				if _, ok := l.fields[fk]; ok {
+					l.log().Printf("<Field %s key:%s> shadowed", ft.Name, fk)
+
+					continue
+				}
+
+				f := newField(fk, fv, tag)
+				f.alloc = alloc
+
+				l.addField(fk, f)
+
+				if tag.HasDefault {
+					l.addDefault(fv, fk, tag.Default)
+				}
+			}
+		}
+	}
+
+	// Embedded structs may be unexported, their exported fields can still be set.
+	for _, i := range squashed {
+		fv := rv.Field(i)
+		ft := rt.Field(i)
+
+		l.log().Printf("<Field %s kind:%s key:%s squashed>", ft.Name, fv.Kind(), key)
+
+		if l.decoder.isStruct(ft.Type) {
+			l.addValidator(fv, key)
+			l.flatten(fv, ft.Type, key, alloc)
+
+			continue
+		}
+
+		// Nil pointers to unexported structs cannot be allocated
+		if !fv.CanSet() {
+			if fv.IsNil() {
+				continue
 			}
+
+			l.addValidator(fv, key)
+			l.flatten(fv.Elem(), ft.Type.Elem(), key, alloc)
+
+			continue
 		}
+
+		pf := newPointerField(key, fv, Tag{}, alloc)
+
+		l.addValidator(fv, key)
+		l.flatten(pf.shadow.Elem(), ft.Type.Elem(), key, pf.alloc)
+	}
+}
+
+// elem initialises the slices elements up to and including index i. Each element is flattened
+// with its index as its key, e.g servers.0, and its defaults are applied.
+func (l *Loader) elem(sf *sliceField, i int) error {
+	for n := len(sf.elems); n <= i; n++ {
+		key := strings.Join([]string{sf.Key(), strconv.Itoa(n)}, l.delimiter)
+		ev := sf.newElem(n)
+
+		ef := newField(key, ev.Elem(), Tag{})
+		ef.alloc = sf.commit
+
+		l.addField(key, ef)
+		l.addValidator(ev.Elem(), key)
+		l.flatten(ev.Elem(), ev.Elem().Type(), key, sf.commit)
+
+		if err := l.elemDefaults(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mapElem initialises the maps element for the map key if it does not exist. Like slices the
+// element is flattened with the map key as its key, e.g backends.eu, and its defaults are applied.
+func (l *Loader) mapElem(mf *structMapField, mk string) error {
+	if _, ok := mf.elems[mk]; ok {
+		return nil
+	}
+
+	key := strings.Join([]string{mf.Key(), mk}, l.delimiter)
+	ev := mf.newElem(mk)
+	commit := mf.commit(mk)
+
+	ef := newField(key, ev.Elem(), Tag{})
+	ef.alloc = commit
+
+	l.addField(key, ef)
+	l.addValidator(ev.Elem(), key)
+	l.flatten(ev.Elem(), ev.Elem().Type(), key, commit)
+
+	return l.elemDefaults(key)
+}
+
+// elemDefaults applies the defaults of a newly created slice or map element.
+func (l *Loader) elemDefaults(key string) error {
+	prefix := key + l.delimiter
+	values := make(map[string]interface{})
+
+	for k, v := range l.defaults.values {
+		if strings.HasPrefix(k, prefix) {
+			values[k] = v
+		}
+	}
+
+	for k, v := range values {
+		// Elements are not always recreated, so these are not left to the defaults parser
+		l.defaults.Delete(k)
+
+		// Committed elements already hold their values
+		if l.rebinding {
+			continue
+		}
+
+		if err := l.setValue(l.defaultsParser, k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeElems removes the fields and validators of slice or map elements below the given key.
+func (l *Loader) removeElems(key string) {
+	prefix := key + l.delimiter
+
+	for key := range l.fields {
+		if strings.HasPrefix(key, prefix) {
+			delete(l.fields, key)
+		}
+	}
+
+	validators := l.validators[:0]
+
+	for _, v := range l.validators {
+		if !strings.HasPrefix(v.key, prefix) {
+			validators = append(validators, v)
+		}
+	}
+
+	l.validators = validators
+}
+
+// elementKeys returns the keys of the slices elements with a * in place of the index, e.g
+// servers.*.host. Parsers such as environment variables use these to find indexed keys.
+func (l *Loader) elementKeys(sf *sliceField, seen map[reflect.Type]bool) []string {
+	et := sf.elemType()
+
+	// Guard against recursive types
+	if seen[et] {
+		return nil
+	}
+
+	seen[et] = true
+	defer delete(seen, et)
+
+	tmp := newLoader(l.opts...)
+	tmp.flatten(reflect.New(et).Elem(), et, strings.Join([]string{sf.Key(), "*"}, l.delimiter), nil)
+
+	keys := make([]string, 0, len(tmp.fields))
+
+	for k, f := range tmp.fields {
+		keys = append(keys, k)
+
+		if sf, ok := f.(*sliceField); ok {
+			keys = append(keys, tmp.elementKeys(sf, seen)...)
+		}
+	}
+
+	return keys
+}
+
+// addField adds the field, the field decodes values with the Loaders decoding options.
+func (l *Loader) addField(key string, f Field) {
+	if d, ok := f.(decodable); ok {
+		d.setDecoder(l.decoder)
+	}
+
+	l.fields.Set(key, f)
+}
+
+// expandable returns true if the field can be set key by key from a map of values. Dynamic fields
+// are set with the whole map.
+func (l *Loader) expandable(field Field) bool {
+	t := field.Value().Type()
+
+	return !isDynamic(t) && !l.decoder.isLeaf(t) &&
+		(l.decoder.isStruct(t) || t.Kind() == reflect.Map || l.decoder.isStructPtr(t))
+}
+
+// isEmpty returns true for nil values and empty strings. Zero values such as false and 0 are not
+// empty, they are set like any other value whichever parser they come from.
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.Len() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	}
+
+	return false
+}
+
+// toMap converts maps with string keys, e.g map[string]interface{}, to a map[string]interface{}.
+func toMap(v interface{}) (map[string]interface{}, bool) {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m, true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	m := make(map[string]interface{}, rv.Len())
+
+	for _, k := range rv.MapKeys() {
+		m[k.String()] = rv.MapIndex(k).Interface()
+	}
+
+	return m, true
+}
+
+// isStructSlice returns true if the type is a slice of structs or struct pointers.
+func (d decoder) isStructSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && (d.isStruct(t.Elem()) || d.isStructPtr(t.Elem()))
+}
+
+// isStructMap returns true if the type is a map of string keys to structs or struct pointers.
+func (d decoder) isStructMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String &&
+		(d.isStruct(t.Elem()) || d.isStructPtr(t.Elem()))
+}
+
+// isStruct returns true if the type is a struct that is flattened into its fields. Leaf structs
+// such as time.Time are set as a single value.
+func (d decoder) isStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !d.isLeaf(t)
+}
+
+// isStructPtr returns true if the type is a pointer to a struct.
+func (d decoder) isStructPtr(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && d.isStruct(t.Elem())
+}
+
+// addDefault adds a fields default value to the defaults parser. Map defaults are given as comma
+// separated key:value pairs, e.g default=foo:bar,fizz:buzz.
+func (l *Loader) addDefault(fv reflect.Value, key string, value string) {
+	if fv.Kind() != reflect.Map {
+		l.defaults.Add(key, value)
+
+		return
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		elms := strings.SplitN(pair, ":", 2)
+		if len(elms) != 2 {
+			l.log().Printf("%s invalid map default: %s", key, pair)
+
+			continue
+		}
+
+		l.defaults.Add(strings.Join([]string{key, strings.TrimSpace(elms[0])}, l.delimiter), elms[1])
 	}
 }
@@ -1,7 +1,21 @@
 package gofig
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -224,3 +238,2250 @@ func TestKeyFormatting(t *testing.T) {
 		})
 	}
 }
+
+type upper string
+
+func (u *upper) UnmarshalGoFig(v interface{}) error {
+	s, ok := v.(string)
+	if !ok {
+		return ErrInvalidConversion{From: reflect.TypeOf(v).Kind(), To: reflect.String}
+	}
+
+	*u = upper(strings.ToUpper(s))
+
+	return nil
+}
+
+func TestDefaults(t *testing.T) {
+	type Config struct {
+		Host  string            `gofig:"host,default=localhost"`
+		Port  int               `gofig:"port,default=8080"`
+		Slice []int             `gofig:"slice,default=1,2,3"`
+		Map   map[string]string `gofig:"map,default=foo:bar,fizz:buzz"`
+		Upper upper             `gofig:"upper,omitempty,default=foo"`
+	}
+
+	cases := map[string]struct {
+		parser *InMemoryParser
+		opts   []Option
+		want   Config
+	}{
+		"Defaults": {
+			parser: NewInMemoryParser(),
+			want: Config{
+				Host:  "localhost",
+				Port:  8080,
+				Slice: []int{1, 2, 3},
+				Map: map[string]string{
+					"foo":  "bar",
+					"fizz": "buzz",
+				},
+				Upper: "FOO",
+			},
+		},
+		"Override": {
+			parser: func() *InMemoryParser {
+				p := NewInMemoryParser()
+				p.Add("port", 9090)
+				p.Add("slice", []int{4})
+				p.Add("map.foo", "baz")
+
+				return p
+			}(),
+			want: Config{
+				Host:  "localhost",
+				Port:  9090,
+				Slice: []int{4},
+				Map: map[string]string{
+					"foo":  "baz",
+					"fizz": "buzz",
+				},
+				Upper: "FOO",
+			},
+		},
+		"NoEnforcePriority": {
+			opts: []Option{
+				SetEnforcePriority(false),
+			},
+			parser: func() *InMemoryParser {
+				p := NewInMemoryParser()
+				p.Add("host", "example.com")
+
+				return p
+			}(),
+			want: Config{
+				Host:  "example.com",
+				Port:  8080,
+				Slice: []int{1, 2, 3},
+				Map: map[string]string{
+					"foo":  "bar",
+					"fizz": "buzz",
+				},
+				Upper: "FOO",
+			},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+
+			opts := append(
+				tc.opts,
+				WithDebug(),
+				SetLogger(LoggerFunc(func(v ...interface{}) {
+					t.Log(v...)
+				})))
+
+			g, err := New(&cfg, opts...)
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if err := g.Parse(tc.parser); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if !cmp.Equal(tc.want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, cfg)
+			}
+		})
+	}
+}
+
+type namedParser struct {
+	*InMemoryParser
+}
+
+func (p namedParser) KeyName(key string) string {
+	return strings.ToUpper(strings.Replace(key, ".", "_", -1))
+}
+
+func TestCheckRequired(t *testing.T) {
+	type Config struct {
+		DB struct {
+			URL  string `gofig:"url,required"`
+			Port int    `gofig:"port,required,default=5432"`
+		} `gofig:"db"`
+		Name string            `gofig:"name,required"`
+		Map  map[string]string `gofig:"map,required"`
+		TLS  *struct {
+			Cert string `gofig:"cert,required"`
+			Key  string `gofig:"key"`
+		} `gofig:"tls"`
+	}
+
+	cases := map[string]struct {
+		parser Parser
+		want   []MissingKey
+	}{
+		"AllMissing": {
+			parser: namedParser{NewInMemoryParser()},
+			want: []MissingKey{
+				{Key: "db.url", Names: []string{"DB_URL"}},
+				{Key: "map", Names: []string{"MAP"}},
+				{Key: "name", Names: []string{"NAME"}},
+			},
+		},
+		"SomeMissing": {
+			parser: func() Parser {
+				p := NewInMemoryParser()
+				p.Add("name", "foo")
+				p.Add("map.foo", "bar")
+
+				return p
+			}(),
+			want: []MissingKey{
+				{Key: "db.url"},
+			},
+		},
+		"OptionalSection": {
+			parser: func() Parser {
+				p := NewInMemoryParser()
+				p.Add("db.url", "postgres://localhost")
+				p.Add("name", "foo")
+				p.Add("map.foo", "bar")
+				p.Add("tls.key", "key.pem")
+
+				return p
+			}(),
+			want: []MissingKey{
+				{Key: "tls.cert"},
+			},
+		},
+		"NoneMissing": {
+			parser: func() Parser {
+				p := NewInMemoryParser()
+				p.Add("db.url", "postgres://localhost")
+				p.Add("name", "foo")
+				p.Add("map.foo", "bar")
+
+				return p
+			}(),
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+
+			g, err := New(&cfg, SetLogger(NopLogger()))
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if err := g.Parse(tc.parser); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			err = g.CheckRequired()
+
+			if tc.want == nil {
+				if err != nil {
+					t.Fatal("want nil error, got:", err)
+				}
+
+				return
+			}
+
+			var missing ErrMissingKeys
+			if !errors.As(err, &missing) {
+				t.Fatalf("want ErrMissingKeys, got: %v", err)
+			}
+
+			t.Log(err)
+
+			if !cmp.Equal(tc.want, missing.Keys) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, missing.Keys)
+			}
+		})
+	}
+}
+
+type Common struct {
+	Name  string `gofig:"name"`
+	Level string `gofig:"level,default=info"`
+}
+
+type common struct {
+	Region string `gofig:"region"`
+}
+
+func TestEmbedded(t *testing.T) {
+	type Squashed struct {
+		Host string `gofig:"host"`
+	}
+
+	type Config struct {
+		Common
+		common
+		Prefixed Common   `gofig:"prefixed"`
+		Squashed Squashed `gofig:",squash"`
+		Name     string   `gofig:"name"`
+	}
+
+	p := NewInMemoryParser()
+	p.Add("name", "foo")
+	p.Add("region", "eu")
+	p.Add("prefixed.name", "bar")
+	p.Add("host", "localhost")
+
+	want := Config{
+		Common: Common{
+			Level: "info",
+		},
+		common: common{
+			Region: "eu",
+		},
+		Prefixed: Common{
+			Name:  "bar",
+			Level: "info",
+		},
+		Squashed: Squashed{
+			Host: "localhost",
+		},
+		Name: "foo",
+	}
+
+	var cfg Config
+
+	g, err := New(&cfg, WithDebug(), SetLogger(LoggerFunc(func(v ...interface{}) {
+		t.Log(v...)
+	})))
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if err := g.Parse(p); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if !cmp.Equal(want, cfg, cmp.AllowUnexported(Config{})) {
+		t.Errorf("\nwant: %+v\ngot:  %+v", want, cfg)
+	}
+}
+
+func TestPointers(t *testing.T) {
+	type TLS struct {
+		Cert    string `gofig:"cert"`
+		Version string `gofig:"version,default=1.2"`
+	}
+
+	type Config struct {
+		*Common
+		Int *int `gofig:"int"`
+		TLS *TLS `gofig:"tls"`
+		DB  *struct {
+			TLS *TLS `gofig:"tls"`
+		} `gofig:"db"`
+	}
+
+	one := 1
+
+	cases := map[string]struct {
+		values map[string]interface{}
+		cfg    Config
+		want   Config
+	}{
+		"Nil": {
+			values: map[string]interface{}{},
+			want:   Config{},
+		},
+		"Allocate": {
+			values: map[string]interface{}{
+				"int":         1,
+				"name":        "foo",
+				"tls.cert":    "cert.pem",
+				"db.tls.cert": "db.pem",
+			},
+			want: Config{
+				Common: &Common{
+					Name:  "foo",
+					Level: "info",
+				},
+				Int: &one,
+				TLS: &TLS{
+					Cert:    "cert.pem",
+					Version: "1.2",
+				},
+				DB: &struct {
+					TLS *TLS `gofig:"tls"`
+				}{
+					TLS: &TLS{
+						Cert:    "db.pem",
+						Version: "1.2",
+					},
+				},
+			},
+		},
+		"Null": {
+			cfg: Config{
+				Int: &one,
+				TLS: &TLS{
+					Cert: "cert.pem",
+				},
+			},
+			values: map[string]interface{}{
+				"int": nil,
+				"tls": nil,
+			},
+			want: Config{},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tc.cfg
+
+			g, err := New(&cfg, WithDebug(), SetLogger(LoggerFunc(func(v ...interface{}) {
+				t.Log(v...)
+			})))
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			p := NewInMemoryParser()
+			for k, v := range tc.values {
+				p.Add(k, v)
+			}
+
+			if err := g.Parse(p); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if !cmp.Equal(tc.want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, cfg)
+			}
+		})
+	}
+}
+
+func TestNormalisedKeys(t *testing.T) {
+	type DB struct {
+		MaxConns int `gofig:"maxConns"`
+	}
+
+	type Config struct {
+		HTTPTimeout string
+		DB          DB `gofig:"db"`
+	}
+
+	cases := map[string]struct {
+		key string
+	}{
+		"Camel":  {key: "db.maxConns"},
+		"Kebab":  {key: "db.max-conns"},
+		"Snake":  {key: "db.max_conns"},
+		"Pascal": {key: "DB.MaxConns"},
+		"Upper":  {key: "DB.MAX_CONNS"},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+
+			g, err := New(&cfg, SetKeyFormatter(NormalisedKeys()))
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			p := &keysParser{InMemoryParser: NewInMemoryParser()}
+			p.Add("http-timeout", "1s")
+			p.Add(tc.key, 10)
+
+			if err := g.Parse(p); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			want := Config{HTTPTimeout: "1s", DB: DB{MaxConns: 10}}
+			if !cmp.Equal(want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", want, cfg)
+			}
+
+			// Parsers receive the normalised keys, e.g the environment variable DB_MAX_CONNS
+			sort.Strings(p.keys)
+
+			if want := []string{"db.max_conns", "http_timeout"}; !cmp.Equal(want, p.keys) {
+				t.Errorf("want keys %v, got %v", want, p.keys)
+			}
+		})
+	}
+}
+
+type keysParser struct {
+	*InMemoryParser
+
+	keys []string
+}
+
+func (p *keysParser) Keys(c <-chan string) error {
+	for k := range c {
+		p.keys = append(p.keys, k)
+	}
+
+	return nil
+}
+
+func TestStructSlices(t *testing.T) {
+	type Route struct {
+		Path string `gofig:"path"`
+	}
+
+	type TLS struct {
+		Cert string `gofig:"cert"`
+	}
+
+	type Server struct {
+		Host   string  `gofig:"host"`
+		Port   int     `gofig:"port,default=80"`
+		Routes []Route `gofig:"routes"`
+		TLS    TLS     `gofig:"tls"`
+		Client *TLS    `gofig:"client"`
+	}
+
+	type Config struct {
+		Servers  []Server  `gofig:"servers"`
+		Pointers []*Server `gofig:"pointers"`
+		TLS      TLS       `gofig:"tls"`
+	}
+
+	cases := map[string]struct {
+		values map[string]interface{}
+		want   Config
+	}{
+		"List": {
+			values: map[string]interface{}{
+				"servers": []interface{}{
+					map[string]interface{}{
+						"host": "a",
+						"routes": []map[string]interface{}{
+							{"path": "/foo"},
+						},
+					},
+					map[string]interface{}{
+						"host": "b",
+						"port": 8080,
+					},
+				},
+			},
+			want: Config{
+				Servers: []Server{
+					{Host: "a", Port: 80, Routes: []Route{{Path: "/foo"}}},
+					{Host: "b", Port: 8080},
+				},
+			},
+		},
+		"Indexed": {
+			values: map[string]interface{}{
+				"servers.1.host":          "b",
+				"servers.0.host":          "a",
+				"servers.0.routes.0.path": "/foo",
+				"pointers.0.port":         "8080",
+			},
+			want: Config{
+				Servers: []Server{
+					{Host: "a", Port: 80, Routes: []Route{{Path: "/foo"}}},
+					{Host: "b", Port: 80},
+				},
+				Pointers: []*Server{
+					{Port: 8080},
+				},
+			},
+		},
+		"EmptyList": {
+			values: map[string]interface{}{
+				"servers": []interface{}{},
+			},
+			want: Config{
+				Servers: []Server{},
+			},
+		},
+		"NestedSections": {
+			values: map[string]interface{}{
+				"servers": []interface{}{
+					map[string]interface{}{
+						"host": "a",
+						"tls":  map[string]interface{}{"cert": "a.pem"},
+					},
+				},
+				"tls": map[string]interface{}{"cert": "default.pem"},
+			},
+			want: Config{
+				Servers: []Server{
+					{Host: "a", Port: 80, TLS: TLS{Cert: "a.pem"}},
+				},
+				TLS: TLS{Cert: "default.pem"},
+			},
+		},
+		"NestedPointer": {
+			values: map[string]interface{}{
+				"servers.0.client.cert": "client.pem",
+			},
+			want: Config{
+				Servers: []Server{
+					{Port: 80, Client: &TLS{Cert: "client.pem"}},
+				},
+			},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+
+			g, err := New(&cfg, WithDebug(), SetLogger(LoggerFunc(func(v ...interface{}) {
+				t.Log(v...)
+			})))
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			p := NewInMemoryParser()
+			for k, v := range tc.values {
+				p.Add(k, v)
+			}
+
+			if err := g.Parse(p); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if !cmp.Equal(tc.want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, cfg)
+			}
+		})
+	}
+
+	t.Run("ElementKeys", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg Config
+
+		g, err := New(&cfg, SetLogger(NopLogger()))
+		if err != nil {
+			t.Fatal("want nil error, got:", err)
+		}
+
+		p := &keysParser{InMemoryParser: NewInMemoryParser()}
+		if err := g.Parse(p); err != nil {
+			t.Fatal("want nil error, got:", err)
+		}
+
+		want := []string{
+			"pointers",
+			"pointers.*.client",
+			"pointers.*.client.cert",
+			"pointers.*.host",
+			"pointers.*.port",
+			"pointers.*.routes",
+			"pointers.*.routes.*.path",
+			"pointers.*.tls.cert",
+			"servers",
+			"servers.*.client",
+			"servers.*.client.cert",
+			"servers.*.host",
+			"servers.*.port",
+			"servers.*.routes",
+			"servers.*.routes.*.path",
+			"servers.*.tls.cert",
+			"tls.cert",
+		}
+
+		sort.Strings(p.keys)
+
+		if !cmp.Equal(want, p.keys) {
+			t.Errorf("\nwant: %+v\ngot:  %+v", want, p.keys)
+		}
+	})
+}
+
+func TestStructMaps(t *testing.T) {
+	type TLS struct {
+		Cert string `gofig:"cert"`
+	}
+
+	type Backend struct {
+		Host    string `gofig:"host"`
+		Timeout int    `gofig:"timeout,default=30"`
+		TLS     TLS    `gofig:"tls"`
+		Client  *TLS   `gofig:"client"`
+	}
+
+	type Config struct {
+		Backends map[string]Backend  `gofig:"backends"`
+		Pointers map[string]*Backend `gofig:"pointers"`
+	}
+
+	cases := map[string]struct {
+		cfg    Config
+		values map[string]interface{}
+		want   Config
+	}{
+		"Keys": {
+			values: map[string]interface{}{
+				"backends.eu.timeout": 10,
+				"backends.us.host":    "us.example.com",
+				"pointers.eu.host":    "eu.example.com",
+			},
+			want: Config{
+				Backends: map[string]Backend{
+					"eu": {Timeout: 10},
+					"us": {Host: "us.example.com", Timeout: 30},
+				},
+				Pointers: map[string]*Backend{
+					"eu": {Host: "eu.example.com", Timeout: 30},
+				},
+			},
+		},
+		"Map": {
+			values: map[string]interface{}{
+				"backends": map[string]interface{}{
+					"eu": map[string]interface{}{
+						"host": "eu.example.com",
+					},
+				},
+			},
+			want: Config{
+				Backends: map[string]Backend{
+					"eu": {Host: "eu.example.com", Timeout: 30},
+				},
+			},
+		},
+		"NestedSection": {
+			values: map[string]interface{}{
+				"backends.eu.tls": map[string]interface{}{"cert": "eu.pem"},
+			},
+			want: Config{
+				Backends: map[string]Backend{
+					"eu": {Timeout: 30, TLS: TLS{Cert: "eu.pem"}},
+				},
+			},
+		},
+		"NestedPointer": {
+			values: map[string]interface{}{
+				"backends.eu.client.cert": "client.pem",
+			},
+			want: Config{
+				Backends: map[string]Backend{
+					"eu": {Timeout: 30, Client: &TLS{Cert: "client.pem"}},
+				},
+			},
+		},
+		"Existing": {
+			cfg: Config{
+				Backends: map[string]Backend{
+					"eu": {Host: "eu.example.com"},
+				},
+			},
+			values: map[string]interface{}{
+				"backends.eu.timeout": 5,
+			},
+			want: Config{
+				Backends: map[string]Backend{
+					"eu": {Host: "eu.example.com", Timeout: 5},
+				},
+			},
+		},
+		"Null": {
+			cfg: Config{
+				Backends: map[string]Backend{
+					"eu": {Host: "eu.example.com"},
+				},
+			},
+			values: map[string]interface{}{
+				"backends": nil,
+			},
+			want: Config{},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tc.cfg
+
+			g, err := New(&cfg, WithDebug(), SetLogger(LoggerFunc(func(v ...interface{}) {
+				t.Log(v...)
+			})))
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			p := NewInMemoryParser()
+			for k, v := range tc.values {
+				p.Add(k, v)
+			}
+
+			if err := g.Parse(p); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if !cmp.Equal(tc.want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, cfg)
+			}
+		})
+	}
+}
+
+func TestDynamic(t *testing.T) {
+	type Config struct {
+		Any     interface{}            `gofig:"any"`
+		Blob    interface{}            `gofig:"blob"`
+		Plugins map[string]interface{} `gofig:"plugins"`
+		List    []interface{}          `gofig:"list"`
+		Ints    map[string]int         `gofig:"ints"`
+	}
+
+	p := NewInMemoryParser()
+	p.Add("any", 1)
+	p.Add("blob.foo.bar", "baz")
+	p.Add("blob.foo.fizz", "buzz")
+	p.Add("plugins.auth.keys", []interface{}{"a", "b"})
+	p.Add("plugins.auth.ttl", 10)
+	p.Add("plugins.cache", map[string]interface{}{"size": 1})
+	p.Add("list", []interface{}{1, "two", map[string]interface{}{"three": 3}})
+	p.Add("ints", map[string]interface{}{"one": 1})
+
+	want := Config{
+		Any: 1,
+		Blob: map[string]interface{}{
+			"foo": map[string]interface{}{
+				"bar":  "baz",
+				"fizz": "buzz",
+			},
+		},
+		Plugins: map[string]interface{}{
+			"auth": map[string]interface{}{
+				"keys": []interface{}{"a", "b"},
+				"ttl":  10,
+			},
+			"cache": map[string]interface{}{
+				"size": 1,
+			},
+		},
+		List: []interface{}{1, "two", map[string]interface{}{"three": 3}},
+		Ints: map[string]int{"one": 1},
+	}
+
+	var cfg Config
+
+	g, err := New(&cfg, WithDebug(), SetLogger(LoggerFunc(func(v ...interface{}) {
+		t.Log(v...)
+	})))
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if err := g.Parse(p); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if !cmp.Equal(want, cfg) {
+		t.Errorf("\nwant: %+v\ngot:  %+v", want, cfg)
+	}
+}
+
+func TestTime(t *testing.T) {
+	type Server struct {
+		Host    string        `gofig:"host"`
+		Timeout time.Duration `gofig:"timeout,default=5s"`
+	}
+
+	type Config struct {
+		Timeout  time.Duration  `gofig:"timeout"`
+		Grace    time.Duration  `gofig:"grace,unit=s"`
+		Start    time.Time      `gofig:"start"`
+		Day      time.Time      `gofig:"day,layout=02 Jan 2006"`
+		Zone     *time.Location `gofig:"zone"`
+		Servers  []Server       `gofig:"servers"`
+		Deadline *time.Time     `gofig:"deadline"`
+	}
+
+	p := NewInMemoryParser()
+	p.Add("timeout", "30s")
+	p.Add("grace", 10)
+	p.Add("start", "2020-04-01T12:00:00Z")
+	p.Add("day", "01 Apr 2020")
+	p.Add("zone", "UTC")
+	p.Add("servers", []interface{}{map[string]interface{}{"host": "a"}})
+	p.Add("deadline", time.Date(2020, 4, 2, 0, 0, 0, 0, time.UTC))
+
+	deadline := time.Date(2020, 4, 2, 0, 0, 0, 0, time.UTC)
+
+	want := Config{
+		Timeout:  30 * time.Second,
+		Grace:    10 * time.Second,
+		Start:    time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC),
+		Day:      time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC),
+		Zone:     time.UTC,
+		Servers:  []Server{{Host: "a", Timeout: 5 * time.Second}},
+		Deadline: &deadline,
+	}
+
+	var cfg Config
+
+	g, err := New(&cfg, WithDebug(), SetLogger(LoggerFunc(func(v ...interface{}) {
+		t.Log(v...)
+	})))
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if err := g.Parse(p); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	// Locations are compared by name
+	loc := cmp.Comparer(func(a, b *time.Location) bool {
+		return a.String() == b.String()
+	})
+
+	if !cmp.Equal(want, cfg, loc) {
+		t.Errorf("\nwant: %+v\ngot:  %+v", want, cfg)
+	}
+}
+
+func TestTextAndJSONUnmarshalers(t *testing.T) {
+	type Config struct {
+		IP     net.IP `gofig:"ip"`
+		Level  *level `gofig:"level,default=info"`
+		Origin point  `gofig:"origin"`
+	}
+
+	p := NewInMemoryParser()
+	p.Add("ip", "10.0.0.1")
+	p.Add("origin", []interface{}{1, 2})
+
+	info := level(1)
+
+	want := Config{
+		IP:     net.ParseIP("10.0.0.1"),
+		Level:  &info,
+		Origin: point{X: 1, Y: 2},
+	}
+
+	var cfg Config
+
+	g, err := New(&cfg, WithJSONUnmarshaler(), WithDebug(), SetLogger(LoggerFunc(func(v ...interface{}) {
+		t.Log(v...)
+	})))
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if err := g.Parse(p); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if !cmp.Equal(want, cfg) {
+		t.Errorf("\nwant: %+v\ngot:  %+v", want, cfg)
+	}
+}
+
+type endpoint struct {
+	Host string
+	Port int
+}
+
+func (e *endpoint) UnmarshalGoFig(v interface{}) error {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected endpoint %v", v)
+	}
+
+	e.Host, _ = m["host"].(string)
+	e.Port, _ = m["port"].(int)
+
+	return nil
+}
+
+type s3 struct {
+	Bucket string `gofig:"bucket"`
+	Region string `gofig:"region,default=eu-west-1"`
+}
+
+type disk struct {
+	Path string `gofig:"path"`
+}
+
+type backend struct {
+	Key    string
+	Parser string
+	Impl   interface{}
+}
+
+func (b *backend) UnmarshalGoFigContext(ctx UnmarshalContext, v interface{}) error {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected backend %v", v)
+	}
+
+	b.Key = ctx.Key
+	b.Parser = parserName(ctx.Parser)
+
+	typ := m["type"]
+	delete(m, "type")
+
+	switch typ {
+	case "s3":
+		var s s3
+		if err := ctx.Decode(m, &s); err != nil {
+			return err
+		}
+
+		b.Impl = s
+	case "disk":
+		var d disk
+		if err := ctx.Decode(m, &d); err != nil {
+			return err
+		}
+
+		b.Impl = d
+	default:
+		return fmt.Errorf("unknown backend type %v", typ)
+	}
+
+	return nil
+}
+
+func TestUnmarshalers(t *testing.T) {
+	type Config struct {
+		Endpoint endpoint  `gofig:"endpoint"`
+		Storage  backend   `gofig:"storage"`
+		Backups  []backend `gofig:"backups"`
+		Cache    *backend  `gofig:"cache"`
+	}
+
+	cases := map[string]struct {
+		values map[string]interface{}
+		want   Config
+		err    bool
+	}{
+		"Subtree": {
+			values: map[string]interface{}{
+				"endpoint.host":  "localhost",
+				"endpoint.port":  8080,
+				"storage.type":   "s3",
+				"storage.bucket": "assets",
+				"cache.type":     "disk",
+				"cache.path":     "/tmp",
+				"backups": []interface{}{
+					map[string]interface{}{"type": "disk", "path": "/mnt"},
+				},
+			},
+			want: Config{
+				Endpoint: endpoint{Host: "localhost", Port: 8080},
+				Storage: backend{
+					Key:    "storage",
+					Parser: "memory",
+					Impl:   s3{Bucket: "assets", Region: "eu-west-1"},
+				},
+				Backups: []backend{
+					{Key: "backups", Parser: "memory", Impl: disk{Path: "/mnt"}},
+				},
+				Cache: &backend{
+					Key:    "cache",
+					Parser: "memory",
+					Impl:   disk{Path: "/tmp"},
+				},
+			},
+		},
+		"Map": {
+			values: map[string]interface{}{
+				"storage": map[string]interface{}{"type": "disk", "path": "/var"},
+			},
+			want: Config{
+				Storage: backend{
+					Key:    "storage",
+					Parser: "memory",
+					Impl:   disk{Path: "/var"},
+				},
+			},
+		},
+		"Unknown": {
+			values: map[string]interface{}{
+				"storage.type": "tape",
+			},
+			err: true,
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewInMemoryParser()
+			for k, v := range tc.values {
+				p.Add(k, v)
+			}
+
+			var cfg Config
+
+			g, err := New(&cfg, WithDebug(), SetLogger(LoggerFunc(func(v ...interface{}) {
+				t.Log(v...)
+			})))
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			err = g.Parse(p)
+			if tc.err {
+				if err == nil {
+					t.Fatal("want error, got nil")
+				}
+
+				t.Log(err)
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if !cmp.Equal(tc.want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, cfg)
+			}
+		})
+	}
+}
+
+func TestStrict(t *testing.T) {
+	type Config struct {
+		DB struct {
+			MaxConns int    `gofig:"max_conns"`
+			Host     string `gofig:"host"`
+		} `gofig:"db"`
+	}
+
+	cases := map[string]struct {
+		perParser bool // only the strict parser is strict
+		strict    map[string]interface{}
+		loose     map[string]interface{}
+		want      *ErrUnknownKey
+	}{
+		"Known": {
+			strict: map[string]interface{}{"db.max_conns": 1},
+		},
+		"Suggestion": {
+			strict: map[string]interface{}{"db.maxconns": 1},
+			want: &ErrUnknownKey{
+				Key:        "db.maxconns",
+				Suggestion: "db.max_conns",
+			},
+		},
+		"NestedKnown": {
+			strict: map[string]interface{}{
+				"db": map[string]interface{}{"max_conns": 1, "host": "localhost"},
+			},
+		},
+		"NestedSuggestion": {
+			strict: map[string]interface{}{
+				"db": map[string]interface{}{"max_connz": 1},
+			},
+			want: &ErrUnknownKey{
+				Key:        "db.max_connz",
+				Suggestion: "db.max_conns",
+			},
+		},
+		"NoSuggestion": {
+			strict: map[string]interface{}{"logging.level": "info"},
+			want: &ErrUnknownKey{
+				Key: "logging.level",
+			},
+		},
+		"PerParser": {
+			perParser: true,
+			strict:    map[string]interface{}{"db.hots": "localhost"},
+			loose:     map[string]interface{}{"db.hots": "localhost"},
+			want: &ErrUnknownKey{
+				Key:        "db.hots",
+				Suggestion: "db.host",
+			},
+		},
+		"Loose": {
+			perParser: true,
+			loose:     map[string]interface{}{"db.hots": "localhost"},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			strict := NewInMemoryParser()
+			for k, v := range tc.strict {
+				strict.Add(k, v)
+			}
+
+			loose := NewInMemoryParser()
+			for k, v := range tc.loose {
+				loose.Add(k, v)
+			}
+
+			opt := WithStrict()
+			if tc.perParser {
+				opt = WithStrict(strict)
+			}
+
+			var cfg Config
+
+			g, err := New(&cfg, opt)
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			err = g.Parse(loose, strict)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatal("want nil error, got:", err)
+				}
+
+				return
+			}
+
+			var uerr ErrUnknownKey
+			if !errors.As(err, &uerr) {
+				t.Fatalf("want ErrUnknownKey, got: %v", err)
+			}
+
+			t.Log(err)
+
+			if uerr.Key != tc.want.Key || uerr.Suggestion != tc.want.Suggestion {
+				t.Errorf("want %s (%s), got %s (%s)", tc.want.Key, tc.want.Suggestion, uerr.Key, uerr.Suggestion)
+			}
+
+			if uerr.Parser != strict {
+				t.Errorf("want parser %v, got %v", strict, uerr.Parser)
+			}
+		})
+	}
+}
+
+type locatingParser struct {
+	*InMemoryParser
+}
+
+func (p locatingParser) Locate(key string) (string, int) {
+	return "config.yaml", len(key)
+}
+
+func TestParseErrors(t *testing.T) {
+	type Config struct {
+		Port    int    `gofig:"port"`
+		Debug   bool   `gofig:"debug"`
+		Level   string `gofig:"level,oneof=debug info"`
+		Servers []struct {
+			Weight uint `gofig:"weight"`
+		} `gofig:"servers"`
+	}
+
+	p := locatingParser{NewInMemoryParser()}
+	p.Add("port", "eighty")
+	p.Add("debug", "maybe")
+	p.Add("level", "loud")
+	p.Add("servers", []interface{}{
+		map[string]interface{}{"weight": 1},
+		map[string]interface{}{"weight": -1},
+	})
+
+	var cfg Config
+
+	g, err := New(&cfg)
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	err = g.Parse(p)
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+
+	t.Log(err)
+
+	var errs *MultiError
+	if !errors.As(err, &errs) {
+		t.Fatalf("want MultiError, got: %v", err)
+	}
+
+	var keys []string
+
+	for _, err := range errs.Errors() {
+		var fe FieldError
+		if errors.As(err, &fe) {
+			if fe.File != "config.yaml" || fe.Line != len(fe.Key) || fe.Parser != p {
+				t.Errorf("want %s located in config.yaml by the parser, got: %+v", fe.Key, fe)
+			}
+
+			keys = append(keys, fe.Key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	if want := []string{"debug", "port", "servers.1.weight"}; !cmp.Equal(want, keys) {
+		t.Errorf("want field errors for %v, got %v", want, keys)
+	}
+
+	var verr ErrValidation
+	if !errors.As(err, &verr) || verr.Key != "level" {
+		t.Errorf("want validation error for level, got: %v", verr)
+	}
+}
+
+func TestStructTags(t *testing.T) {
+	type Config struct {
+		Host    string `yaml:"host" json:"hostname"`
+		Port    int    `gofig:"port" json:"p"`
+		Debug   bool   `mapstructure:"debug"`
+		Secret  string `json:"-"`
+		Timeout int    `gofig:",default=5" yaml:"timeout"`
+	}
+
+	cases := map[string]struct {
+		opts []Option
+		want Config
+	}{
+		"Default": {
+			want: Config{Port: 80, Secret: "shh", Timeout: 5},
+		},
+		"SetStructTag": {
+			opts: []Option{SetStructTag("json")},
+			want: Config{Host: "example.com", Port: 8080},
+		},
+		"SetStructTags": {
+			opts: []Option{SetStructTags("gofig", "yaml", "json", "mapstructure")},
+			want: Config{Host: "localhost", Port: 80, Debug: true, Timeout: 10},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewInMemoryParser()
+			p.Add("host", "localhost")
+			p.Add("hostname", "example.com")
+			p.Add("port", 80)
+			p.Add("p", 8080)
+			p.Add("debug", true)
+			p.Add("Secret", "shh")
+			p.Add("timeout", 10)
+
+			var cfg Config
+
+			g, err := New(&cfg, tc.opts...)
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if err := g.Parse(p); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if !cmp.Equal(tc.want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, cfg)
+			}
+		})
+	}
+}
+
+func TestOmitEmpty(t *testing.T) {
+	type Config struct {
+		Host  string   `gofig:"host,omitempty"`
+		Port  int      `gofig:"port,omitempty,default=80"`
+		User  string   `gofig:"user"`
+		Tags  []string `gofig:"tags"`
+		Debug bool     `gofig:"debug,default=true"`
+	}
+
+	cases := map[string]struct {
+		opts    []Option
+		layers  bool // env is given a higher priority and parsed first
+		strings bool // env gives false and 0 as strings
+		want    Config
+	}{
+		"Tag": {
+			want: Config{Host: "db", Tags: []string{}},
+		},
+		"Layers": {
+			layers: true,
+			want:   Config{Host: "db", Tags: []string{}},
+		},
+		"Option": {
+			opts: []Option{WithOmitEmpty()},
+			want: Config{Host: "db", User: "admin", Tags: []string{"a"}},
+		},
+		"OptionStrings": {
+			opts:    []Option{WithOmitEmpty()},
+			strings: true,
+			want:    Config{Host: "db", User: "admin", Tags: []string{"a"}},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file := NewInMemoryParser()
+			file.Add("host", "db")
+			file.Add("port", 8080)
+			file.Add("user", "admin")
+			file.Add("tags", []interface{}{"a"})
+
+			env := NewInMemoryParser()
+			env.Add("host", "")
+			env.Add("user", "")
+			env.Add("tags", "")
+
+			if tc.strings {
+				env.Add("port", "0")
+				env.Add("debug", "false")
+			} else {
+				env.Add("port", 0)
+				env.Add("debug", false)
+			}
+
+			var cfg Config
+
+			opts, parsers := tc.opts, []Parser{file, env}
+			if tc.layers {
+				opts = append(opts, WithPriority(env, LayerEnv), WithPriority(file, LayerFile))
+				parsers = []Parser{env, file}
+			}
+
+			g, err := New(&cfg, opts...)
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if err := g.Parse(parsers...); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if !cmp.Equal(tc.want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, cfg)
+			}
+		})
+	}
+}
+
+func TestSetNamingStrategy(t *testing.T) {
+	type Config struct {
+		HTTPTimeout string
+		MaxConns    int
+		Host        string `gofig:"HOST"`
+		DB          struct {
+			UserName string
+		}
+	}
+
+	cases := map[string]struct {
+		strategy NamingStrategy
+		values   map[string]interface{}
+	}{
+		"Default": {
+			values: map[string]interface{}{"HTTPTimeout": "1s", "MaxConns": 2, "HOST": "db", "DB.UserName": "admin"},
+		},
+		"Snake": {
+			strategy: SnakeCase(),
+			values:   map[string]interface{}{"http_timeout": "1s", "max_conns": 2, "HOST": "db", "db.user_name": "admin"},
+		},
+		"Kebab": {
+			strategy: KebabCase(),
+			values:   map[string]interface{}{"http-timeout": "1s", "max-conns": 2, "HOST": "db", "db.user-name": "admin"},
+		},
+		"Camel": {
+			strategy: CamelCase(),
+			values:   map[string]interface{}{"httpTimeout": "1s", "maxConns": 2, "HOST": "db", "db.userName": "admin"},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+
+			var opts []Option
+			if tc.strategy != nil {
+				opts = append(opts, SetNamingStrategy(tc.strategy))
+			}
+
+			g, err := New(&cfg, opts...)
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			p := NewInMemoryParser()
+			for k, v := range tc.values {
+				p.Add(k, v)
+			}
+
+			if err := g.Parse(p); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			want := Config{HTTPTimeout: "1s", MaxConns: 2, Host: "db"}
+			want.DB.UserName = "admin"
+
+			if !cmp.Equal(want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", want, cfg)
+			}
+		})
+	}
+}
+
+func TestAliases(t *testing.T) {
+	type DB struct {
+		MaxConnections int `gofig:"max_connections,alias=maxconns max_conns,deprecated=pool.max"`
+	}
+
+	type Config struct {
+		DB DB `gofig:"db,alias=database"`
+	}
+
+	cases := map[string]struct {
+		values   map[string]interface{}
+		want     int
+		warnings []string
+	}{
+		"Name": {
+			values: map[string]interface{}{"db.max_connections": 10},
+			want:   10,
+		},
+		"Alias": {
+			values:   map[string]interface{}{"db.maxconns": 10},
+			want:     10,
+			warnings: []string{"db.maxconns from memory is deprecated, use db.max_connections"},
+		},
+		"SecondAlias": {
+			values:   map[string]interface{}{"db.max_conns": 10},
+			want:     10,
+			warnings: []string{"db.max_conns from memory is deprecated, use db.max_connections"},
+		},
+		"Deprecated": {
+			values:   map[string]interface{}{"pool.max": 10},
+			want:     10,
+			warnings: []string{"pool.max from memory is deprecated, use db.max_connections"},
+		},
+		"ParentAlias": {
+			values:   map[string]interface{}{"database.max_connections": 10},
+			want:     10,
+			warnings: []string{"database from memory is deprecated, use db"},
+		},
+		"NameWins": {
+			values:   map[string]interface{}{"db.max_connections": 10, "pool.max": 5},
+			want:     10,
+			warnings: []string{"pool.max from memory is deprecated, use db.max_connections"},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				cfg      Config
+				warnings []string
+			)
+
+			g, err := New(&cfg, SetLogger(LoggerFunc(func(v ...interface{}) {
+				warnings = append(warnings, fmt.Sprint(v...))
+			})))
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			p := NewInMemoryParser()
+			for k, v := range tc.values {
+				p.Add(k, v)
+			}
+
+			if err := g.Parse(p); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if cfg.DB.MaxConnections != tc.want {
+				t.Errorf("want %d, got %d", tc.want, cfg.DB.MaxConnections)
+			}
+
+			if !cmp.Equal(tc.warnings, warnings) {
+				t.Errorf("\nwant: %q\ngot:  %q", tc.warnings, warnings)
+			}
+		})
+	}
+}
+
+func TestWithPriority(t *testing.T) {
+	type Config struct {
+		Host string `gofig:"host"`
+		Port int    `gofig:"port"`
+		User string `gofig:"user"`
+	}
+
+	file := NewInMemoryParser()
+	file.Add("host", "file")
+	file.Add("port", 1)
+	file.Add("user", "file")
+
+	env := NewInMemoryParser()
+	env.Add("host", "env")
+
+	flags := NewInMemoryParser()
+	flags.Add("port", 3)
+
+	other := NewInMemoryParser()
+	other.Add("host", "other")
+	other.Add("user", "other")
+
+	cases := map[string]struct {
+		order  []Parser
+		reload bool
+		want   Config
+	}{
+		"Ordered": {
+			order: []Parser{other, file, env, flags},
+			want:  Config{Host: "env", Port: 3, User: "file"},
+		},
+		"Reversed": {
+			order: []Parser{flags, env, file, other},
+			want:  Config{Host: "env", Port: 3, User: "file"},
+		},
+		"ParsedAgain": {
+			order: []Parser{file, env, flags, file},
+			want:  Config{Host: "env", Port: 3, User: "file"},
+		},
+		"Reload": {
+			order:  []Parser{flags, file, other, env},
+			reload: true,
+			want:   Config{Host: "env", Port: 3, User: "file"},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+
+			g, err := New(&cfg,
+				WithPriority(file, LayerFile),
+				WithPriority(env, LayerEnv),
+				WithPriority(flags, LayerFlags))
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			for _, p := range tc.order {
+				if err := g.Parse(p); err != nil {
+					t.Fatal("want nil error, got:", err)
+				}
+			}
+
+			if tc.reload {
+				if err := g.Reload(); err != nil {
+					t.Fatal("want nil error, got:", err)
+				}
+			}
+
+			if !cmp.Equal(tc.want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, cfg)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	type DB struct {
+		Name string `gofig:"name"`
+	}
+
+	type Config struct {
+		Host    string            `gofig:"host,default=localhost"`
+		Port    int               `gofig:"port"`
+		User    string            `gofig:"user"`
+		Labels  map[string]string `gofig:"labels"`
+		Servers []DB              `gofig:"servers"`
+		DB      *DB               `gofig:"db"`
+	}
+
+	cases := map[string]struct {
+		remove []string
+		want   Config
+	}{
+		"Unchanged": {
+			want: Config{
+				Host:    "file",
+				Port:    2,
+				User:    "file",
+				Labels:  map[string]string{"a": "initial", "b": "file"},
+				Servers: []DB{{Name: "file"}},
+				DB:      &DB{Name: "file"},
+			},
+		},
+		"LowerLayer": {
+			remove: []string{"port"},
+			want: Config{
+				Host:    "file",
+				Port:    1,
+				User:    "file",
+				Labels:  map[string]string{"a": "initial", "b": "file"},
+				Servers: []DB{{Name: "file"}},
+				DB:      &DB{Name: "file"},
+			},
+		},
+		"Default": {
+			remove: []string{"host"},
+			want: Config{
+				Host:    "localhost",
+				Port:    2,
+				User:    "file",
+				Labels:  map[string]string{"a": "initial", "b": "file"},
+				Servers: []DB{{Name: "file"}},
+				DB:      &DB{Name: "file"},
+			},
+		},
+		"Initial": {
+			remove: []string{"user", "labels.b", "servers", "db.name"},
+			want: Config{
+				Host:    "file",
+				Port:    2,
+				User:    "initial",
+				Labels:  map[string]string{"a": "initial"},
+				Servers: []DB{{Name: "initial"}},
+			},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := Config{
+				User:    "initial",
+				Labels:  map[string]string{"a": "initial"},
+				Servers: []DB{{Name: "initial"}},
+			}
+
+			g, err := New(&cfg)
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			base := NewInMemoryParser()
+			base.Add("port", 1)
+
+			file := NewInMemoryParser()
+			file.Add("host", "file")
+			file.Add("port", 2)
+			file.Add("user", "file")
+			file.Add("labels.b", "file")
+			file.Add("servers", []interface{}{map[string]interface{}{"name": "file"}})
+			file.Add("db.name", "file")
+
+			if err := g.Parse(base, file); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			for _, k := range tc.remove {
+				file.Delete(k)
+			}
+
+			if err := g.Reload(); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			if !cmp.Equal(tc.want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, cfg)
+			}
+		})
+	}
+}
+
+func TestReloadRollback(t *testing.T) {
+	type Config struct {
+		Host string   `gofig:"host"`
+		Port int      `gofig:"port,max=9000"`
+		Tags []string `gofig:"tags"`
+	}
+
+	type change struct {
+		key   string
+		value interface{}
+	}
+
+	veto := BeforeApplyFunc(func(current, next interface{}) error {
+		if next.(*Config).Host == "" {
+			return errors.New("host removed")
+		}
+
+		return nil
+	})
+
+	cases := map[string]struct {
+		changes []change
+		remove  []string
+		want    Config
+		err     bool
+	}{
+		"Applied": {
+			changes: []change{{"port", 9000}, {"tags", []interface{}{"b"}}},
+			want:    Config{Host: "db", Port: 9000, Tags: []string{"b"}},
+		},
+		"InvalidValue": {
+			changes: []change{{"tags", []interface{}{"b"}}, {"port", "eighty"}},
+			want:    Config{Host: "db", Port: 80, Tags: []string{"a"}},
+			err:     true,
+		},
+		"Validation": {
+			changes: []change{{"tags", []interface{}{"b"}}, {"port", 9001}},
+			want:    Config{Host: "db", Port: 80, Tags: []string{"a"}},
+			err:     true,
+		},
+		"Vetoed": {
+			changes: []change{{"tags", []interface{}{"b"}}},
+			remove:  []string{"host"},
+			want:    Config{Host: "db", Port: 80, Tags: []string{"a"}},
+			err:     true,
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+
+			g, err := New(&cfg, WithBeforeApply(veto))
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			p := NewInMemoryParser()
+			p.Add("host", "db")
+			p.Add("port", 80)
+			p.Add("tags", []interface{}{"a"})
+
+			if err := g.Parse(p); err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			for _, c := range tc.changes {
+				p.Add(c.key, c.value)
+			}
+
+			for _, k := range tc.remove {
+				p.Delete(k)
+			}
+
+			err = g.Reload()
+			if tc.err && err == nil {
+				t.Error("want error, got nil")
+			}
+
+			if !tc.err && err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			t.Log(err)
+
+			if !cmp.Equal(tc.want, cfg) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, cfg)
+			}
+		})
+	}
+}
+
+type countingParser struct {
+	*InMemoryParser
+
+	n int64
+}
+
+func (p *countingParser) Values() (<-chan func() (string, interface{}), error) {
+	n := atomic.AddInt64(&p.n, 1)
+
+	ch := make(chan func() (string, interface{}), 1)
+	ch <- func() (string, interface{}) {
+		return "count", n
+	}
+
+	close(ch)
+
+	return ch, nil
+}
+
+func TestSnapshots(t *testing.T) {
+	type Config struct {
+		Host  string         `gofig:"host,default=localhost"`
+		Count int64          `gofig:"count"`
+		Tags  map[string]int `gofig:"tags,default=a:1"`
+		Loc   *time.Location `gofig:"loc,default=UTC"`
+	}
+
+	var cfg Config
+
+	g, err := New(&cfg)
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	first := g.Current().(*Config)
+	// Locations are compared by pointer
+	loc := cmp.Comparer(func(a, b *time.Location) bool {
+		return a == b
+	})
+
+	if want := (Config{Host: "localhost", Tags: map[string]int{"a": 1}, Loc: time.UTC}); !cmp.Equal(want, *first, loc) {
+		t.Fatalf("\nwant: %+v\ngot:  %+v", want, *first)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watch := g.Watch(ctx)
+
+	p := &countingParser{InMemoryParser: NewInMemoryParser()}
+
+	if err := g.Parse(p); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if s := (<-watch).(*Config); s.Count != 1 {
+		t.Fatalf("want count 1, got: %d", s.Count)
+	}
+
+	var wg sync.WaitGroup
+
+	// Reload and read concurrently, snapshots only move forward
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 25; i++ {
+				if err := g.Reload(); err != nil {
+					t.Error("want nil error, got:", err)
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			var last int64
+
+			for i := 0; i < 100; i++ {
+				s := g.Current().(*Config)
+				if s.Count < last {
+					t.Errorf("want count >= %d, got: %d", last, s.Count)
+				}
+
+				last = s.Count
+
+				if _, ok := g.Explain("count"); !ok {
+					t.Error("want count explained")
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if s := g.Current().(*Config); s.Count != 101 {
+		t.Errorf("want count 101, got: %d", s.Count)
+	}
+
+	// Snapshots are copies, earlier snapshots are unchanged
+	if first.Count != 0 || first.Tags["a"] != 1 {
+		t.Errorf("want first snapshot unchanged, got: %+v", *first)
+	}
+
+	cancel()
+
+	// The latest snapshot may still be buffered, the channel is then closed
+	for range watch {
+	}
+}
+
+func TestSnapshotsUnchanged(t *testing.T) {
+	type Config struct {
+		Big  *big.Int `gofig:"big"`
+		Port int      `gofig:"port"`
+	}
+
+	const (
+		small = "123456789012345678901234567890"
+		large = "987654321098765432109876543210"
+	)
+
+	var cfg Config
+
+	g, err := New(&cfg)
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	p := NewInMemoryParser()
+	p.Add("big", small)
+
+	if err := g.Parse(p); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	first := g.Current().(*Config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watch := g.Watch(ctx)
+
+	// A failed parse keeps the current configuration and publishes nothing
+	invalid := NewInMemoryParser()
+	invalid.Add("big", large)
+	invalid.Add("port", "eighty")
+
+	if err := g.Parse(invalid); err == nil {
+		t.Fatal("want error, got nil")
+	}
+
+	select {
+	case s := <-watch:
+		t.Errorf("want no snapshot, got: %+v", s)
+	default:
+	}
+
+	if cfg.Big.String() != small || g.Current() != first {
+		t.Errorf("want configuration unchanged, got: %+v", cfg)
+	}
+
+	// Values are replaced rather than decoded into, earlier snapshots are unchanged
+	valid := NewInMemoryParser()
+	valid.Add("big", large)
+
+	if err := g.Parse(valid); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if s := (<-watch).(*Config); s.Big.String() != large {
+		t.Errorf("want %s, got: %s", large, s.Big)
+	}
+
+	if first.Big.String() != small {
+		t.Errorf("want first snapshot unchanged, got: %s", first.Big)
+	}
+}
+
+// linesParser parses key=value lines.
+type linesParser struct{}
+
+func (linesParser) SetDelimeter(string) {}
+
+func (linesParser) Values(src io.ReadCloser) (<-chan func() (string, interface{}), error) {
+	defer src.Close()
+
+	b, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) == 0 {
+		return nil, io.EOF
+	}
+
+	ch := make(chan func() (string, interface{}), bytes.Count(b, []byte("\n"))+1)
+
+	for _, line := range strings.Split(string(b), "\n") {
+		kv := strings.SplitN(line, "=", 2)
+
+		ch <- func() (string, interface{}) {
+			return kv[0], kv[1]
+		}
+	}
+
+	close(ch)
+
+	return ch, nil
+}
+
+func (p linesParser) LocateValues(src io.ReadCloser) (<-chan func() (string, interface{}), func(string) int, error) {
+	defer src.Close()
+
+	b, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lines := make(map[string]int)
+
+	for i, line := range strings.Split(string(b), "\n") {
+		lines[strings.SplitN(line, "=", 2)[0]] = i + 1
+	}
+
+	ch, err := p.Values(ioutil.NopCloser(bytes.NewReader(b)))
+
+	return ch, func(key string) int { return lines[key] }, err
+}
+
+func TestLineLocator(t *testing.T) {
+	type Config struct {
+		Host  string `gofig:"host"`
+		Port  int    `gofig:"port"`
+		Debug bool   `gofig:"debug"`
+	}
+
+	var cfg Config
+
+	g, err := New(&cfg)
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	// Both sources share a parser, each locates keys within its own source
+	var p linesParser
+
+	err = g.Parse(FromString(p, "host=db\nport=eighty"), FromString(p, "debug=maybe"))
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+
+	var errs *MultiError
+	if !errors.As(err, &errs) {
+		t.Fatalf("want MultiError, got: %v", err)
+	}
+
+	lines := make(map[string]int)
+
+	for _, err := range errs.Errors() {
+		var fe FieldError
+		if errors.As(err, &fe) {
+			lines[fe.Key] = fe.Line
+		}
+	}
+
+	if want := map[string]int{"port": 2, "debug": 1}; !cmp.Equal(want, lines) {
+		t.Errorf("\nwant: %+v\ngot:  %+v", want, lines)
+	}
+}
+
+func TestReloadReadOnce(t *testing.T) {
+	type Config struct {
+		Host string `gofig:"host"`
+		Port int    `gofig:"port"`
+	}
+
+	var cfg Config
+
+	g, err := New(&cfg)
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	str := FromString(linesParser{}, "host=str\nport=1")
+
+	file := NewInMemoryParser()
+	file.Add("port", 2)
+
+	if err := g.Parse(str, file); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	file.Add("port", 3)
+
+	// The string cannot be read again, the values it returned are reused
+	if err := g.Reload(); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if want := (Config{Host: "str", Port: 3}); !cmp.Equal(want, cfg) {
+		t.Errorf("\nwant: %+v\ngot:  %+v", want, cfg)
+	}
+}
+
+// decodeCounter counts how many times it is unmarshaled.
+type decodeCounter struct {
+	n *int64
+	v string
+}
+
+func (c *decodeCounter) UnmarshalGoFig(v interface{}) error {
+	atomic.AddInt64(c.n, 1)
+
+	c.v = fmt.Sprint(v)
+
+	return nil
+}
+
+func TestReloadCommit(t *testing.T) {
+	type Config struct {
+		Host    string        `gofig:"host"`
+		Counter decodeCounter `gofig:"counter"`
+		Servers []struct {
+			Port int `gofig:"port,default=80"`
+		} `gofig:"servers"`
+	}
+
+	var (
+		n    int64
+		next Config
+	)
+
+	cfg := Config{Counter: decodeCounter{n: &n}}
+
+	file := NewInMemoryParser()
+	file.Add("host", "file")
+	file.Add("counter", "a")
+	file.Add("servers.0.port", 8080)
+
+	low := NewInMemoryParser()
+	low.Add("host", "low")
+
+	g, err := New(&cfg,
+		WithPriority(file, LayerFile),
+		WithPriority(low, LayerDefaults),
+		WithBeforeApply(BeforeApplyFunc(func(_, n interface{}) error {
+			next = *n.(*Config)
+
+			return nil
+		})))
+	if err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if err := g.Parse(file); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	file.Add("host", "reloaded")
+
+	if err := g.Reload(); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	// Values are decoded once when staged, the staged copy is committed
+	if got := atomic.LoadInt64(&n); got != 2 {
+		t.Errorf("want 2 decodes, got: %d", got)
+	}
+
+	if !cmp.Equal(next, cfg, cmp.AllowUnexported(decodeCounter{})) {
+		t.Errorf("\nwant: %+v\ngot:  %+v", next, cfg)
+	}
+
+	if cfg.Host != "reloaded" || cfg.Servers[0].Port != 8080 {
+		t.Errorf("want reloaded host and port 8080, got: %+v", cfg)
+	}
+
+	// Fields keep the parser and priority that set them
+	e, ok := g.Explain("host")
+	if !ok || e.Parser == nil || parserName(e.Parser) != "memory" || len(e.Candidates) != 1 {
+		t.Errorf("want host explained, got: %+v", e)
+	}
+
+	if err := g.Parse(low); err != nil {
+		t.Fatal("want nil error, got:", err)
+	}
+
+	if cfg.Host != "reloaded" {
+		t.Errorf("want host reloaded, got: %s", cfg.Host)
+	}
+}
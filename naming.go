@@ -0,0 +1,125 @@
+package gofig
+
+import (
+	"strings"
+	"unicode"
+)
+
+// A NamingStrategy names struct fields that are not named by a struct tag.
+type NamingStrategy interface {
+	Name(field string) string
+}
+
+// NamingStrategyFunc is an adapter function allowing regular methods to act as NamingStrategy's.
+type NamingStrategyFunc func(string) string
+
+// Name names the field calling the wrapped fn.
+func (fn NamingStrategyFunc) Name(field string) string {
+	return fn(field)
+}
+
+// FieldNames returns a NamingStrategy that uses the Go field name, e.g MaxConns, this is the
+// default.
+func FieldNames() NamingStrategy {
+	return NamingStrategyFunc(func(field string) string {
+		return field
+	})
+}
+
+// SnakeCase returns a NamingStrategy that names fields in snake case, e.g HTTPTimeout is named
+// http_timeout.
+func SnakeCase() NamingStrategy {
+	return NamingStrategyFunc(func(field string) string {
+		return strings.ToLower(strings.Join(words(field), "_"))
+	})
+}
+
+// KebabCase returns a NamingStrategy that names fields in kebab case, e.g HTTPTimeout is named
+// http-timeout.
+func KebabCase() NamingStrategy {
+	return NamingStrategyFunc(func(field string) string {
+		return strings.ToLower(strings.Join(words(field), "-"))
+	})
+}
+
+// CamelCase returns a NamingStrategy that names fields in camel case, e.g HTTPTimeout is named
+// httpTimeout.
+func CamelCase() NamingStrategy {
+	return NamingStrategyFunc(func(field string) string {
+		w := words(field)
+
+		for i := range w {
+			w[i] = strings.ToLower(w[i])
+
+			if i > 0 {
+				r := []rune(w[i])
+				r[0] = unicode.ToUpper(r[0])
+				w[i] = string(r)
+			}
+		}
+
+		return strings.Join(w, "")
+	})
+}
+
+// LowerCase returns a NamingStrategy that names fields in lower case, e.g HTTPTimeout is named
+// httptimeout.
+func LowerCase() NamingStrategy {
+	return NamingStrategyFunc(func(field string) string {
+		return strings.ToLower(field)
+	})
+}
+
+// words splits a Go field name into words. Acronyms are kept together, e.g HTTPTimeout is split
+// into HTTP and Timeout, as are plural acronyms, e.g UserIDs is split into User and IDs. Digits
+// belong to the word before them, e.g V2API is split into V2 and API.
+func words(s string) []string {
+	var (
+		w     []string
+		r     = []rune(s)
+		start int
+	)
+
+	for i := 1; i < len(r); i++ {
+		prev, cur := r[i-1], r[i]
+
+		var next rune
+		if i+1 < len(r) {
+			next = r[i+1]
+		}
+
+		switch {
+		case cur == '_' || cur == '-':
+			if i > start {
+				w = append(w, string(r[start:i]))
+			}
+
+			start = i + 1
+
+			continue
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			// fooBar, foo2Bar
+		case unicode.IsUpper(cur) && unicode.IsUpper(prev) && unicode.IsLower(next) && !plural(r[i+1:]):
+			// HTTPTimeout, the T starts Timeout
+		default:
+			continue
+		}
+
+		if i > start {
+			w = append(w, string(r[start:i]))
+		}
+
+		start = i
+	}
+
+	if start < len(r) {
+		w = append(w, string(r[start:]))
+	}
+
+	return w
+}
+
+// plural returns true if the runes start with an s ending the word, e.g the s of IDs.
+func plural(r []rune) bool {
+	return len(r) > 0 && r[0] == 's' && (len(r) == 1 || !unicode.IsLower(r[1]))
+}
@@ -0,0 +1,67 @@
+package gofig
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNamingStrategy(t *testing.T) {
+	cases := map[string]struct {
+		field string
+		want  map[string]string
+	}{
+		"Word": {
+			field: "Host",
+			want:  map[string]string{"snake": "host", "kebab": "host", "camel": "host", "lower": "host"},
+		},
+		"Words": {
+			field: "MaxConns",
+			want:  map[string]string{"snake": "max_conns", "kebab": "max-conns", "camel": "maxConns", "lower": "maxconns"},
+		},
+		"LeadingAcronym": {
+			field: "HTTPTimeout",
+			want:  map[string]string{"snake": "http_timeout", "kebab": "http-timeout", "camel": "httpTimeout", "lower": "httptimeout"},
+		},
+		"TrailingAcronym": {
+			field: "UserID",
+			want:  map[string]string{"snake": "user_id", "kebab": "user-id", "camel": "userId", "lower": "userid"},
+		},
+		"PluralAcronym": {
+			field: "UserIDs",
+			want:  map[string]string{"snake": "user_ids", "kebab": "user-ids", "camel": "userIds", "lower": "userids"},
+		},
+		"Digits": {
+			field: "V2API",
+			want:  map[string]string{"snake": "v2_api", "kebab": "v2-api", "camel": "v2Api", "lower": "v2api"},
+		},
+		"Underscores": {
+			field: "Max_Conns",
+			want:  map[string]string{"snake": "max_conns", "kebab": "max-conns", "camel": "maxConns", "lower": "max_conns"},
+		},
+	}
+
+	strategies := map[string]NamingStrategy{
+		"snake": SnakeCase(),
+		"kebab": KebabCase(),
+		"camel": CamelCase(),
+		"lower": LowerCase(),
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := make(map[string]string)
+			for n, s := range strategies {
+				got[n] = s.Name(tc.field)
+			}
+
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("\nwant: %v\ngot:  %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -27,9 +27,14 @@ func (l *Loader) Notify(c chan<- error, notifiers ...NotifyParser) {
 	l.NotifyWithContext(context.Background(), c, notifiers...)
 }
 
-// NotifyWithContext notifies when a change to configuration has occurred.
+// NotifyWithContext notifies when a change to configuration has occurred. On each change every
+// parser is parsed again, see Reload, so keys removed from the changed parser revert to lower
+// priority parsers.
 func (l *Loader) NotifyWithContext(ctx context.Context, c chan<- error, notifiers ...NotifyParser) {
+	l.mu.Lock()
 	l.notifiers = append(l.notifiers, notifiers...)
+	l.mu.Unlock()
+
 	l.wg.Add(len(notifiers))
 
 	for _, n := range notifiers {
@@ -47,7 +52,9 @@ func (l *Loader) NotifyWithContext(ctx context.Context, c chan<- error, notifier
 					}
 
 					if err == nil {
-						err = l.Parse(n)
+						l.mu.Lock()
+						err = l.reload(true, n)
+						l.mu.Unlock()
 					}
 
 					c <- err
@@ -57,12 +64,16 @@ func (l *Loader) NotifyWithContext(ctx context.Context, c chan<- error, notifier
 	}
 }
 
-// Close stops listening for notification events. This only needs to be called if Notify or
-// NotifyWithContext are being used.
+// Close stops listening for notification events and closes the channels returned by Watch. This
+// only needs to be called if Notify, NotifyWithContext or Watch are being used.
 func (l *Loader) Close() error {
 	var err CloseError
 
-	for _, n := range l.notifiers {
+	l.mu.Lock()
+	notifiers := append([]NotifyParser(nil), l.notifiers...)
+	l.mu.Unlock()
+
+	for _, n := range notifiers {
 		if e := n.Close(); e != nil {
 			err.Add(e)
 		}
@@ -70,6 +81,12 @@ func (l *Loader) Close() error {
 
 	l.wg.Wait()
 
+	l.mu.Lock()
+	for ch := range l.watchers {
+		l.unwatch(ch)
+	}
+	l.mu.Unlock()
+
 	return err.NilOrError()
 }
 
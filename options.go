@@ -1,5 +1,7 @@
 package gofig
 
+import "reflect"
+
 // An Option configures gofig.
 type Option interface {
 	apply(*Loader)
@@ -26,10 +28,45 @@ func SetKeyFormatter(formatter Formatter) Option {
 	})
 }
 
+// SetNamingStrategy sets the strategy used to name struct fields that are not named by a struct tag,
+// e.g SnakeCase names the field MaxConns max_conns. By default the field name is used.
+func SetNamingStrategy(s NamingStrategy) Option {
+	return OptionFunc(func(l *Loader) {
+		l.namingStrategy = s
+	})
+}
+
 // SetStructTag changes the struct tag gofig looks for on struct fields to the value provided.
 func SetStructTag(t string) Option {
+	return SetStructTags(t)
+}
+
+// SetStructTags sets the struct tags gofig looks for on struct fields in order, the first tag
+// present on a field is used, e.g:
+//
+//	gofig.SetStructTags("gofig", "yaml", "json", "mapstructure")
+//
+// This allows structs already tagged for other decoders to be used without duplicating their tags.
+func SetStructTags(tags ...string) Option {
 	return OptionFunc(func(l *Loader) {
-		l.structTag = t
+		l.structTags = tags
+	})
+}
+
+// WithPriority sets the priority of a parser rather than ranking it by the order it is parsed in.
+// Values from higher priority parsers override lower priority parsers, parsers with the same
+// priority override each other in the order they are parsed. See LayerFile, LayerEnv etc.
+func WithPriority(p Parser, n int) Option {
+	return OptionFunc(func(l *Loader) {
+		l.parsers.Add(p).SetPriority(n)
+	})
+}
+
+// WithBeforeApply adds a hook called before reloads are applied, the hook can veto the reload. See
+// Reload.
+func WithBeforeApply(h BeforeApply) Option {
+	return OptionFunc(func(l *Loader) {
+		l.beforeApply = append(l.beforeApply, h)
 	})
 }
 
@@ -40,6 +77,68 @@ func SetEnforcePriority(v bool) Option {
 	})
 }
 
+// WithJSONUnmarshaler enables setting values on types implementing json.Unmarshaler, such as
+// structs decoding their own fields, the value is encoded as JSON and passed to UnmarshalJSON.
+// Types implementing encoding.TextUnmarshaler are always set from strings.
+func WithJSONUnmarshaler() Option {
+	return OptionFunc(func(l *Loader) {
+		l.decoder.json = true
+	})
+}
+
+// WithConverter sets the converter for values set on fields of the given type, replacing any
+// existing converter for the type. Converters are used before any other conversion, fields of the
+// type are not flattened, e.g:
+//
+//	gofig.WithConverter(reflect.TypeOf(Level(0)), func(v interface{}) (interface{}, error) {
+//		return ParseLevel(fmt.Sprint(v))
+//	})
+//
+// Converters for ByteSize, url.URL, regexp.Regexp, net.IPNet and os.FileMode are included.
+func WithConverter(t reflect.Type, fn ConverterFunc) Option {
+	return OptionFunc(func(l *Loader) {
+		l.decoder.converters[t] = fn
+	})
+}
+
+// WithNumberLiterals enables setting integers from strings written as Go integer literals, e.g
+// 0xff, 0o755, 0b1010 or 1_000_000. Note that numbers with a leading 0 are octal.
+func WithNumberLiterals() Option {
+	return OptionFunc(func(l *Loader) {
+		for t, fn := range numberTypes {
+			l.decoder.converters[t] = fn
+		}
+	})
+}
+
+// WithStrict enables strict mode, keys that do not match any field are returned as ErrUnknownKey
+// errors rather than being ignored. Strict mode applies to the given parsers, or to every parser if
+// none are given.
+func WithStrict(parsers ...Parser) Option {
+	return OptionFunc(func(l *Loader) {
+		if len(parsers) == 0 {
+			l.strictAll = true
+		}
+
+		if l.strictParsers == nil {
+			l.strictParsers = make(map[Parser]bool)
+		}
+
+		for _, p := range parsers {
+			l.strictParsers[p] = true
+		}
+	})
+}
+
+// WithOmitEmpty applies the omitempty tag option to every field, empty values such as empty strings
+// do not replace values set by other parsers, e.g an empty environment variable does not replace a
+// value from a file. Only nil values and empty strings are empty, false and 0 are set.
+func WithOmitEmpty() Option {
+	return OptionFunc(func(l *Loader) {
+		l.omitEmpty = true
+	})
+}
+
 // WithDebug enables debugging. Use SetLogger to customise the logging output.
 func WithDebug() Option {
 	return OptionFunc(func(l *Loader) {
@@ -2,17 +2,30 @@ package gofig
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"strings"
 )
 
+// Layer priorities for WithPriority. Parsers in higher layers override parsers in lower layers
+// whatever order they are parsed in. Parsers without a priority are ranked by the order they are
+// first parsed from 1, between the defaults and file layers.
+const (
+	LayerDefaults = 0
+	LayerFile     = 1000
+	LayerRemote   = 2000
+	LayerEnv      = 3000
+	LayerFlags    = 4000
+)
+
 // Parsers stores a map of pointers to PrioritisedParser's.
 type Parsers map[Parser]PrioritisedParser
 
-// Add adds a Parser to the Parsers map returning a PrioritisedParser Parser. The priority of the
-// parser is automatically set based on the size the parsers map.
+// Add adds a Parser to the Parsers map returning a PrioritisedParser Parser. The priority of a new
+// parser is automatically set based on the size the parsers map, parsers already added keep their
+// priority.
 func (p Parsers) Add(parser Parser) PrioritisedParser {
 	prioritised, ok := p[parser]
 	if ok {
@@ -20,7 +33,7 @@ func (p Parsers) Add(parser Parser) PrioritisedParser {
 	}
 
 	prioritised = PrioritiseParser(parser)
-	prioritised.SetPriority(uint8(p.Len() + 1))
+	prioritised.SetPriority(p.Len() + 1)
 
 	p[parser] = prioritised
 
@@ -44,8 +57,8 @@ type DelimeterSetter interface {
 
 // A Prioritiser prioritises a Parser.
 type Prioritiser interface {
-	SetPriority(uint8)
-	Priority() uint8
+	SetPriority(int)
+	Priority() int
 }
 
 // PrioritiseParser wraps a Parser so it can be prioritised.
@@ -58,17 +71,65 @@ func PrioritiseParser(p Parser) PrioritisedParser {
 type prioritised struct {
 	Parser
 
-	priority uint8
+	priority int
 }
 
-func (p *prioritised) SetPriority(v uint8) {
+func (p *prioritised) SetPriority(v int) {
 	p.priority = v
 }
 
-func (p *prioritised) Priority() uint8 {
+func (p *prioritised) Priority() int {
 	return p.priority
 }
 
+func (p *prioritised) String() string {
+	return parserName(p.Parser)
+}
+
+// unwrap returns the Parser wrapped by PrioritiseParser.
+func unwrap(p Parser) Parser {
+	if pp, ok := p.(*prioritised); ok {
+		return pp.Parser
+	}
+
+	return p
+}
+
+// A Locator is implemented by parsers that can locate keys within their source, the file and line
+// are added to errors. Either may be empty if it is not known.
+type Locator interface {
+	Locate(key string) (file string, line int)
+}
+
+// A LineLocator is a ParseReadCloser that finds the line of each key within the sources it parses.
+// The function returned with the values finds lines within that source only, so a parser shared by
+// several files finds lines in the right file.
+type LineLocator interface {
+	LocateValues(src io.ReadCloser) (<-chan func() (string, interface{}), func(key string) int, error)
+}
+
+// locateValues returns the values of the source and, if the parser is a LineLocator, a function
+// finding the line of each key within it.
+func locateValues(p ParseReadCloser, src io.ReadCloser) (<-chan func() (string, interface{}), func(string) int, error) {
+	if ll, ok := p.(LineLocator); ok {
+		return ll.LocateValues(src)
+	}
+
+	ch, err := p.Values(src)
+
+	return ch, nil, err
+}
+
+// parserName returns a human readable name for a Parser, parsers can implement fmt.Stringer to
+// name themselves.
+func parserName(p Parser) string {
+	if s, ok := p.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return fmt.Sprintf("%T", p)
+}
+
 // A Parser parses configuration.
 type Parser interface {
 	DelimeterSetter
@@ -79,6 +140,8 @@ type Parser interface {
 	// converted too FOO_BAR_FIZZ_BUZZ with a mapping to the original key.
 	// This allows us to  maintain case sensitivity in key lookups within the loader.
 	// Most parsers such as YAML, TOML and JSON will not process these keys.
+	// Keys of slice elements are sent with a * in place of the index, e.g servers.*.host, parsers
+	// should return the key with the index, e.g servers.0.host.
 	Keys(keys <-chan string) error
 
 	// Values returns a channel of functions that returns an individual key value pair.
@@ -102,6 +165,20 @@ type Parser interface {
 	Values() (<-chan func() (key string, value interface{}), error)
 }
 
+// A Reloadable Parser can return its values more than once, for example by reading its file again.
+// Reloads parse reloadable parsers again, the values other parsers last returned are reused, such
+// as a ReadCloseParser whose reader can only be read once. Notifiers are always parsed again when
+// they notify.
+type Reloadable interface {
+	Reloadable() bool
+}
+
+// A KeyNamer is a Parser that looks up keys by a source specific name, for example the environment
+// variable name FOO_BAR for the key foo.bar. It is used to name missing required keys in errors.
+type KeyNamer interface {
+	KeyName(key string) string
+}
+
 // A PrioritisedParser is a Parser that has been prioritised.
 type PrioritisedParser interface {
 	Parser
@@ -147,6 +224,16 @@ func (p *InMemoryParser) Delete(k string) {
 // SetDelimeter is a no-op.
 func (p *InMemoryParser) SetDelimeter(string) {}
 
+// Reloadable returns true, the values are held in memory.
+func (p *InMemoryParser) Reloadable() bool {
+	return true
+}
+
+// String names the parser.
+func (p *InMemoryParser) String() string {
+	return "memory"
+}
+
 // Keys consumes the keys but does nothing with them.
 func (p *InMemoryParser) Keys(c <-chan string) error {
 	for {
@@ -196,11 +283,22 @@ func (p *InMemoryParser) Close() error {
 	return nil
 }
 
-// ReadCloseParser parses config from io.ReadCloser's.
+// defaultsParser holds default values from struct tags.
+type defaultsParser struct {
+	*InMemoryParser
+}
+
+func (defaultsParser) String() string {
+	return "defaults"
+}
+
+// ReadCloseParser parses config from io.ReadCloser's. The reader is read once, reloads reuse the
+// values it returned.
 type ReadCloseParser struct {
 	parser   ParseReadCloser
 	src      io.ReadCloser
-	priority uint8
+	priority int
+	lines    func(key string) int // lines of keys within src, see LineLocator
 }
 
 // NewReadCloseParser constructs a new ReadCloseParser.
@@ -216,13 +314,18 @@ func (p *ReadCloseParser) SetDelimeter(d string) {
 	p.parser.SetDelimeter(d)
 }
 
+// String names the parser by the type of ParseReadCloser it wraps.
+func (p *ReadCloseParser) String() string {
+	return fmt.Sprintf("%T", p.parser)
+}
+
 // SetPriority sets the parsers priority.
-func (p *ReadCloseParser) SetPriority(v uint8) {
+func (p *ReadCloseParser) SetPriority(v int) {
 	p.priority = v
 }
 
 // Priority returns parsers priority.
-func (p *ReadCloseParser) Priority() uint8 {
+func (p *ReadCloseParser) Priority() int {
 	return p.priority
 }
 
@@ -238,7 +341,23 @@ func (p *ReadCloseParser) Keys(c <-chan string) error {
 
 // Values returns values from the parser back to gofig.
 func (p *ReadCloseParser) Values() (<-chan func() (string, interface{}), error) {
-	return p.parser.Values(p.src)
+	ch, lines, err := locateValues(p.parser, p.src)
+	if err != nil {
+		return nil, err
+	}
+
+	p.lines = lines
+
+	return ch, nil
+}
+
+// Locate returns the line of the key if the ParseReadCloser it wraps is a LineLocator.
+func (p *ReadCloseParser) Locate(key string) (string, int) {
+	if p.lines == nil {
+		return "", 0
+	}
+
+	return "", p.lines(key)
 }
 
 // FromString parsers configuration from a string.
@@ -255,7 +374,8 @@ func FromBytes(parser ParseReadCloser, b []byte) Parser {
 type FileParser struct {
 	parser   ParseReadCloser
 	path     string
-	priority uint8
+	priority int
+	lines    func(key string) int // lines of keys within the file, see LineLocator
 }
 
 // NewFileParser constructs a new FileParser.
@@ -271,13 +391,23 @@ func (p *FileParser) SetDelimeter(d string) {
 	p.parser.SetDelimeter(d)
 }
 
+// String names the parser by the path of the file it parses.
+func (p *FileParser) String() string {
+	return p.path
+}
+
+// Reloadable returns true, the file is opened each time values are read.
+func (p *FileParser) Reloadable() bool {
+	return true
+}
+
 // SetPriority sets the parsers priority.
-func (p *FileParser) SetPriority(v uint8) {
+func (p *FileParser) SetPriority(v int) {
 	p.priority = v
 }
 
 // Priority returns parsers priority.
-func (p *FileParser) Priority() uint8 {
+func (p *FileParser) Priority() int {
 	return p.priority
 }
 
@@ -298,7 +428,26 @@ func (p *FileParser) Values() (<-chan func() (string, interface{}), error) {
 		return nil, err
 	}
 
-	return p.parser.Values(f)
+	ch, lines, err := locateValues(p.parser, f)
+	if err != nil {
+		return nil, err
+	}
+
+	p.lines = lines
+
+	return ch, nil
+}
+
+// Locate returns the files path and the line of the key if the ParseReadCloser it wraps is a
+// LineLocator.
+func (p *FileParser) Locate(key string) (string, int) {
+	var line int
+
+	if p.lines != nil {
+		line = p.lines(key)
+	}
+
+	return p.path, line
 }
 
 // FromFile reads a file.
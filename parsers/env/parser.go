@@ -2,6 +2,7 @@ package env
 
 import (
 	"os"
+	"regexp"
 	"strings"
 )
 
@@ -12,6 +13,39 @@ type Parser struct {
 
 	delimiter string
 	keys      map[string]string
+	patterns  []pattern
+}
+
+// A pattern matches environment variables for keys with a * in place of a slice index, e.g the key
+// servers.*.host matches SERVERS_0_HOST.
+type pattern struct {
+	re  *regexp.Regexp
+	key string
+}
+
+// match returns the key with the matched index, e.g servers.0.host.
+func (p pattern) match(env string) (string, bool) {
+	m := p.re.FindStringSubmatch(env)
+	if m == nil {
+		return "", false
+	}
+
+	key := p.key
+	for _, idx := range m[1:] {
+		key = strings.Replace(key, "*", idx, 1)
+	}
+
+	return key, true
+}
+
+// String names the parser.
+func (p *Parser) String() string {
+	return "env"
+}
+
+// Reloadable returns true, the environment is read each time values are returned.
+func (p *Parser) Reloadable() bool {
+	return true
 }
 
 // SetDelimeter sets the key delimiter.
@@ -21,17 +55,26 @@ func (p *Parser) SetDelimeter(v string) {
 
 // Keys consumes the keys from the channel.
 func (p *Parser) Keys(c <-chan string) error {
+	p.patterns = nil
+
 	// Range over the keys we need to look for and convert to env variables formats.
 	for key := range c {
-		// Break the key at the . delimiter
-		elms := strings.Split(key, p.delimiter)
+		env := p.KeyName(key)
 
-		// Add prefix / suffix
-		elms = append([]string{p.prefix}, elms...)
-		elms = append(elms, p.suffix)
+		// Keys with an index placeholder are matched by pattern
+		if strings.Contains(key, "*") {
+			parts := strings.Split(env, "*")
+			for i := range parts {
+				parts[i] = regexp.QuoteMeta(parts[i])
+			}
 
-		// Join the elements elms together at _
-		env := strings.Trim(strings.ToUpper(strings.Join(elms, "_")), "_")
+			p.patterns = append(p.patterns, pattern{
+				re:  regexp.MustCompile("^" + strings.Join(parts, "([0-9]+)") + "$"),
+				key: key,
+			})
+
+			continue
+		}
 
 		// Store the env var to key mapping
 		p.keys[env] = key
@@ -40,6 +83,19 @@ func (p *Parser) Keys(c <-chan string) error {
 	return nil
 }
 
+// KeyName returns the environment variable name for the given key, e.g foo.bar becomes FOO_BAR.
+func (p *Parser) KeyName(key string) string {
+	// Break the key at the . delimiter
+	elms := strings.Split(key, p.delimiter)
+
+	// Add prefix / suffix
+	elms = append([]string{p.prefix}, elms...)
+	elms = append(elms, p.suffix)
+
+	// Join the elements elms together at _
+	return strings.Trim(strings.ToUpper(strings.Join(elms, "_")), "_")
+}
+
 // Values returns a channel of funcs that return each environment variable key values.
 func (p *Parser) Values() (<-chan func() (string, interface{}), error) {
 	ch := make(chan func() (string, interface{}))
@@ -52,7 +108,7 @@ func (p *Parser) Values() (<-chan func() (string, interface{}), error) {
 			name, val := split(env)
 
 			// Lookup the key, if found, send the key and the value
-			key, ok := p.keys[name]
+			key, ok := p.lookup(name)
 			if ok {
 				ch <- (func(key string, val interface{}) func() (string, interface{}) {
 					return func() (string, interface{}) {
@@ -66,6 +122,21 @@ func (p *Parser) Values() (<-chan func() (string, interface{}), error) {
 	return ch, nil
 }
 
+// lookup returns the key for the environment variable name.
+func (p *Parser) lookup(env string) (string, bool) {
+	if key, ok := p.keys[env]; ok {
+		return key, true
+	}
+
+	for _, pt := range p.patterns {
+		if key, ok := pt.match(env); ok {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
 // split splits an environment string at the = separator returning the key value pair.
 func split(env string) (string, string) {
 	var (
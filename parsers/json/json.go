@@ -29,7 +29,10 @@ func (p *Parser) SetDelimeter(v string) {
 func (p *Parser) Values(src io.ReadCloser) (<-chan func() (string, interface{}), error) {
 	var dst map[string]interface{}
 
+	// Decode numbers as json.Number so they are not truncated to float64
 	d := json.NewDecoder(src)
+	d.UseNumber()
+
 	if err := d.Decode(&dst); err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/pelletier/go-toml"
 )
@@ -59,6 +60,26 @@ func (p *Parser) recurse(key string, m map[string]interface{}, ch chan func() (s
 			return func() (string, interface{}) {
 				return key, val
 			}
-		}(name, v))
+		}(name, value(v)))
 	}
 }
+
+// value converts TOML local date and time values. Like go-toml local dates and date times are
+// converted to a time.Time in the local time zone, local times are converted to strings, e.g
+// 07:32:00, which can be set on time.Time fields with a layout.
+func value(v interface{}) interface{} {
+	switch t := v.(type) {
+	case toml.LocalDateTime:
+		return t.In(time.Local)
+	case toml.LocalDate:
+		return t.In(time.Local)
+	case toml.LocalTime:
+		return t.String()
+	case []interface{}:
+		for i := range t {
+			t[i] = value(t[i])
+		}
+	}
+
+	return v
+}
@@ -3,6 +3,7 @@ package yaml
 import (
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -28,13 +29,31 @@ func (p *Parser) SetDelimeter(v string) {
 // Values parses yaml configuration, iterating over each key value pair and returning them until
 // parsing has been completed.
 func (p *Parser) Values(src io.ReadCloser) (<-chan func() (string, interface{}), error) {
-	var dst map[string]interface{}
+	ch, _, err := p.LocateValues(src)
+
+	return ch, err
+}
+
+// LocateValues parses yaml configuration like Values, also returning a function that returns the
+// line number of each key within src.
+func (p *Parser) LocateValues(src io.ReadCloser) (<-chan func() (string, interface{}), func(key string) int, error) {
+	var (
+		node yaml.Node
+		dst  map[string]interface{}
+	)
 
 	d := yaml.NewDecoder(src)
-	if err := d.Decode(&dst); err != nil {
-		return nil, err
+	if err := d.Decode(&node); err != nil {
+		return nil, nil, err
+	}
+
+	if err := node.Decode(&dst); err != nil {
+		return nil, nil, err
 	}
 
+	lines := make(map[string]int)
+	p.locate(lines, "", &node)
+
 	ch := make(chan func() (string, interface{}))
 
 	go func() {
@@ -42,12 +61,12 @@ func (p *Parser) Values(src io.ReadCloser) (<-chan func() (string, interface{}),
 		p.recurse("", dst, ch)
 	}()
 
-	return ch, src.Close()
+	return ch, func(key string) int { return lines[key] }, src.Close()
 }
 
 func (p *Parser) recurse(key string, m map[string]interface{}, ch chan func() (string, interface{})) {
 	for k, v := range m {
-		name := strings.Trim(strings.Join(append(strings.Split(key, p.delimiter), k), p.delimiter), p.delimiter)
+		name := p.join(key, k)
 
 		if reflect.ValueOf(v).Kind() == reflect.Map {
 			p.recurse(name, v.(map[string]interface{}), ch)
@@ -62,3 +81,32 @@ func (p *Parser) recurse(key string, m map[string]interface{}, ch chan func() (s
 		}(name, v))
 	}
 }
+
+// locate records the line number of each key within the node.
+func (p *Parser) locate(lines map[string]int, key string, n *yaml.Node) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			p.locate(lines, key, c)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			name := p.join(key, n.Content[i].Value)
+
+			lines[name] = n.Content[i].Line
+			p.locate(lines, name, n.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			name := p.join(key, strconv.Itoa(i))
+
+			lines[name] = c.Line
+			p.locate(lines, name, c)
+		}
+	}
+}
+
+// join joins a key to its parent key.
+func (p *Parser) join(parent, key string) string {
+	return strings.Trim(strings.Join(append(strings.Split(parent, p.delimiter), key), p.delimiter), p.delimiter)
+}
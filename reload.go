@@ -0,0 +1,276 @@
+package gofig
+
+import (
+	"reflect"
+	"sort"
+)
+
+// A BeforeApply hook is called before a reload is applied with pointers to the current and next
+// configuration, returning an error vetoes the reload keeping the current configuration. Hooks must
+// not modify either configuration, they are called while the Loader is locked so must not call its
+// methods other than Current.
+type BeforeApply interface {
+	BeforeApply(current, next interface{}) error
+}
+
+// BeforeApplyFunc is an adapter function allowing regular methods to act as BeforeApply hooks.
+type BeforeApplyFunc func(current, next interface{}) error
+
+// BeforeApply calls the wrapped fn.
+func (fn BeforeApplyFunc) BeforeApply(current, next interface{}) error {
+	return fn(current, next)
+}
+
+// Reload parses every parser again from the struct values New was given, the effective
+// configuration is recomputed from all of them. Parsers that are not Reloadable, such as those
+// reading from strings, are not read again, the values they last returned are used. Keys a parser
+// no longer has revert to the value of the next highest priority parser that has them, or the
+// default. Parsers are parsed in order of priority, parsers with the same priority in the order
+// they were first parsed.
+//
+// Reloads are transactional, the configuration is staged in a copy of the struct which is
+// validated and passed to the BeforeApply hooks. Only if every parser, validator and hook succeeds
+// is the configuration applied, otherwise the current configuration is kept and the errors are
+// returned.
+func (l *Loader) Reload() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.reload(true)
+}
+
+// A record holds the values received from a parser so they can be staged and then applied.
+type record struct {
+	parser PrioritisedParser
+	values []keyValue
+}
+
+// reload parses the given parsers, which are new or have changed, and when reloading every
+// Reloadable parser again, reusing the values the other parsers last returned. Parsers added are
+// removed again if the configuration is not applied. BeforeApply hooks are only called when
+// reloading.
+func (l *Loader) reload(reloading bool, parsers ...Parser) error {
+	n := len(l.parsed)
+	changed := make(map[Parser]bool)
+
+	for _, p := range parsers {
+		changed[l.add(p)] = true
+	}
+
+	if err := l.update(reloading, changed); err != nil {
+		l.parsed = l.parsed[:n]
+
+		return err
+	}
+
+	return nil
+}
+
+// update stages the values of every parser, reading them again from the changed parsers and when
+// reloading every Reloadable parser, then commits and publishes the staged configuration.
+func (l *Loader) update(reloading bool, changed map[Parser]bool) error {
+	var (
+		errs    MultiError
+		records []record
+	)
+
+	for _, p := range l.ordered() {
+		values, ok := l.last[p]
+
+		if !ok || changed[p] || (reloading && reloadable(p)) {
+			var err error
+
+			values, err = l.values(p)
+			if err != nil {
+				errs.Add(err)
+
+				continue
+			}
+		}
+
+		records = append(records, record{parser: p, values: values})
+	}
+
+	if err := errs.NilOrError(); err != nil {
+		return err
+	}
+
+	next, err := l.stage(records)
+	if err != nil {
+		return err
+	}
+
+	if reloading {
+		for _, h := range l.beforeApply {
+			if err := h.BeforeApply(l.dst.Addr().Interface(), next.dst.Addr().Interface()); err != nil {
+				return ErrVetoed{Err: err}
+			}
+		}
+	}
+
+	defer l.publish()
+
+	for _, r := range records {
+		l.last[r.parser] = r.values
+	}
+
+	l.commit(next)
+
+	return nil
+}
+
+// reloadable returns true if the parser is Reloadable.
+func reloadable(p Parser) bool {
+	r, ok := unwrap(p).(Reloadable)
+
+	return ok && r.Reloadable()
+}
+
+// ordered returns the parsers in order of priority, parsers with the same priority in the order
+// they were first parsed. Without enforcing priority parsers are in the order they were first
+// parsed, the last parser to set a key wins.
+func (l *Loader) ordered() []PrioritisedParser {
+	ordered := append([]PrioritisedParser(nil), l.parsed...)
+
+	if !l.enforcePriority {
+		return ordered
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority() < ordered[j].Priority()
+	})
+
+	return ordered
+}
+
+// stage sets the values on a copy of the struct as New was given it, returning a Loader holding the
+// validated copy.
+func (l *Loader) stage(records []record) (*Loader, error) {
+	s := newLoader(l.opts...)
+	s.parsers = l.parsers
+	s.initial = l.initial
+	s.dst = reflect.New(l.dst.Type()).Elem()
+	s.dst.Set(deepCopy(l.initial, make(map[uintptr]reflect.Value)))
+
+	var errs MultiError
+
+	errs.Add(s.load())
+
+	for _, r := range records {
+		errs.Add(s.apply(r.parser, r.values))
+	}
+
+	errs.Add(s.validate())
+
+	return s, errs.NilOrError()
+}
+
+// commit sets the struct to the staged copy in one step. The fields are flattened again from the
+// struct, nothing is decoded, and take the state of the staged fields so priorities, parsers and
+// candidates are kept.
+func (l *Loader) commit(s *Loader) {
+	l.dst.Set(s.dst)
+
+	l.fields = make(Fields)
+	l.validators = nil
+	l.aliases = make(map[string]string)
+	l.defaults.values = make(map[string]interface{})
+
+	l.rebinding = true
+	defer func() { l.rebinding = false }()
+
+	l.addValidator(l.dst, "")
+	l.flatten(l.dst, l.dst.Type(), "", nil)
+
+	for key, sf := range s.fields {
+		if f, ok := l.lookup(key); ok && f.Key() == key {
+			f.(stateful).state().adopt(sf.(stateful).state())
+		}
+	}
+}
+
+// add adds the parser to the loaders parsers, recording the order parsers are first parsed in.
+func (l *Loader) add(p Parser) PrioritisedParser {
+	pp := l.parsers.Add(p)
+
+	// Parsers given a priority by WithPriority are added before they are parsed
+	for _, parsed := range l.parsed {
+		if parsed == pp {
+			return pp
+		}
+	}
+
+	l.parsed = append(l.parsed, pp)
+
+	return pp
+}
+
+// deepCopy returns a copy of the value that shares no pointers, maps or slices with it. Pointers
+// already copied are shared in the copy as they are in the value, seen holds them by address.
+// Unexported struct fields are copied shallowly. Locations are immutable so are shared.
+func deepCopy(v reflect.Value, seen map[uintptr]reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+
+	if v.Type() == locationPtrType {
+		c.Set(v)
+
+		return c
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return c
+		}
+
+		if p, ok := seen[v.Pointer()]; ok {
+			return p
+		}
+
+		c.Set(reflect.New(v.Type().Elem()))
+		seen[v.Pointer()] = c
+		c.Elem().Set(deepCopy(v.Elem(), seen))
+	case reflect.Map:
+		if v.IsNil() {
+			return c
+		}
+
+		c.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+
+		for _, k := range v.MapKeys() {
+			c.SetMapIndex(k, deepCopy(v.MapIndex(k), seen))
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return c
+		}
+
+		c.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+	case reflect.Struct:
+		c.Set(v)
+
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i), seen))
+			}
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return c
+		}
+
+		c.Set(deepCopy(v.Elem(), seen))
+	default:
+		c.Set(v)
+	}
+
+	return c
+}
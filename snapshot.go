@@ -0,0 +1,71 @@
+package gofig
+
+import (
+	"context"
+	"reflect"
+)
+
+// Current returns a snapshot of the configuration, a pointer to a copy of the struct given to New,
+// e.g *Config. A new snapshot is published each time parsing or a reload changes the
+// configuration, snapshots are never modified so they are safe to read from any goroutine. Do not
+// modify a snapshot, it is shared by every caller. Locations are immutable so are shared with the
+// struct rather than copied.
+func (l *Loader) Current() interface{} {
+	return l.current.Load()
+}
+
+// Watch returns a channel receiving each new snapshot of the configuration, see Current. The channel
+// is buffered, a snapshot not yet received is replaced by the next. The channel is closed once the
+// context is done or the Loader is closed.
+func (l *Loader) Watch(ctx context.Context) <-chan interface{} {
+	ch := make(chan interface{}, 1)
+	stop := make(chan struct{})
+
+	l.mu.Lock()
+	l.watchers[ch] = stop
+	l.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+			return // Closed by the Loader
+		}
+
+		l.mu.Lock()
+		l.unwatch(ch)
+		l.mu.Unlock()
+	}()
+
+	return ch
+}
+
+// unwatch closes and removes a channel returned by Watch, the loader must be locked.
+func (l *Loader) unwatch(ch chan interface{}) {
+	stop, ok := l.watchers[ch]
+	if !ok {
+		return
+	}
+
+	delete(l.watchers, ch)
+	close(stop)
+	close(ch)
+}
+
+// publish stores a snapshot of the configuration and sends it to the watchers, the loader must be
+// locked.
+func (l *Loader) publish() {
+	snapshot := deepCopy(l.dst, make(map[uintptr]reflect.Value)).Addr().Interface()
+
+	l.current.Store(snapshot)
+
+	for ch := range l.watchers {
+		// Replace a snapshot the watcher has not received yet
+		select {
+		case <-ch:
+		default:
+		}
+
+		ch <- snapshot
+	}
+}
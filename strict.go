@@ -0,0 +1,85 @@
+package gofig
+
+// strict returns true if unknown keys from the parser are errors.
+func (l *Loader) strict(p PrioritisedParser) bool {
+	if p == l.defaultsParser {
+		return false
+	}
+
+	if l.strictAll {
+		return true
+	}
+
+	for parser, pp := range l.parsers {
+		if pp == p {
+			return l.strictParsers[parser]
+		}
+	}
+
+	return false
+}
+
+// suggest returns the known key closest to the given key, or an empty string if no key is close
+// enough to be a likely misspelling.
+func (l *Loader) suggest(key string) string {
+	var (
+		suggestion string
+		best       = -1
+		max        = len(key)/3 + 1 // the maximum number of edits
+	)
+
+	for k := range l.fields {
+		d := distance(key, k)
+		if d > max {
+			continue
+		}
+
+		if best < 0 || d < best || (d == best && k < suggestion) {
+			suggestion = k
+			best = d
+		}
+	}
+
+	return suggestion
+}
+
+// distance returns the Levenshtein distance between two strings, the number of single character
+// insertions, deletions or substitutions needed to change a into b.
+func distance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(br)]
+}
+
+// minInt returns the smallest of the given integers.
+func minInt(v int, vs ...int) int {
+	for _, n := range vs {
+		if n < v {
+			v = n
+		}
+	}
+
+	return v
+}
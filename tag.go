@@ -5,42 +5,186 @@ import (
 	"strings"
 )
 
-const omitempty = "omitempty"
+// Struct tag options.
+const (
+	omitempty  = "omitempty"
+	required   = "required"
+	squash     = "squash"
+	inline     = "inline" // yaml's squash
+	ignore     = "-"
+	optDefault = "default"
+	optMin     = "min"
+	optMax     = "max"
+	optOneOf   = "oneof"
+	optUnit    = "unit"
+	optLayout  = "layout"
+	optAlias   = "alias"
+	optDepr    = "deprecated"
+)
 
 // Tag is a gofig struct tag.
 type Tag struct {
-	Name      string
-	OmitEmpty bool
-	RawTag    string
+	Name       string
+	Named      bool // the name was given by a tag rather than the field name
+	Ignore     bool // the field is not configured, e.g gofig:"-"
+	OmitEmpty  bool // empty values do not replace values set by other parsers
+	Required   bool
+	Squash     bool // promote the fields of a struct to the parents key level
+	Default    string
+	HasDefault bool
+	Min        string   // min=1, minimum value or length
+	Max        string   // max=10, maximum value or length
+	OneOf      []string // oneof=foo bar, space separated allowed values
+	Unit       string   // unit=s, the unit of durations given as numbers
+	Layout     string   // layout=2006-01-02, the layout times are parsed with
+	Aliases    []string // alias=maxconns, old names relative to the parent key
+	Deprecated []string // deprecated=pool.max, old keys from the root
+	RawTag     string
 }
 
 func (t Tag) String() string {
 	return t.RawTag
 }
 
-// TagFromStructField returns a Tag from the struct fields tag.
-func TagFromStructField(field reflect.StructField, tag string) Tag {
+// TagFromStructField returns a Tag from the struct fields tag. Given more than one tag name, such
+// as gofig, yaml and json, the first tag present on the field is used. If it does not name the field
+// the name is taken from the next tag that does. With no tag names DefaultStructTag is used.
+//
+// Options such as default and omitempty are only parsed from the first tag name, the gofig tag.
+// Other tags only name or ignore the field, or inline it, their options such as omitempty are
+// hints for marshalling and do not change how values are set.
+//
+// Option values may contain commas, for example default=1,2,3, any element that is not a known
+// option is treated as part of the previous options value.
+func TagFromStructField(field reflect.StructField, tags ...string) Tag {
 	t := Tag{
 		Name: field.Name,
 	}
 
-	if v, ok := field.Tag.Lookup(DefaultStructTag); ok {
-		t.RawTag = v
+	if len(tags) == 0 {
+		tags = []string{DefaultStructTag}
+	}
 
-		for i, v := range strings.Split(v, ",") {
-			if i == 0 {
-				t.Name = v
+	var found bool
 
-				continue
-			}
+	for _, tag := range tags {
+		v, ok := field.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
 
-			if v == omitempty {
-				t.OmitEmpty = true
+		if v == ignore {
+			t.Ignore = !found
 
-				continue
+			break
+		}
+
+		if !found {
+			found = true
+			t.RawTag = v
+
+			if tag == tags[0] {
+				t.parseOptions(v)
+			} else {
+				t.parseInline(v)
 			}
 		}
+
+		if name := strings.Split(v, ",")[0]; name != "" {
+			t.Name = name
+			t.Named = true
+
+			break
+		}
+	}
+
+	// Like Go, embedded structs without a name have their fields promoted.
+	if field.Anonymous && !t.Named && (decoder{}.isStruct(field.Type) || decoder{}.isStructPtr(field.Type)) {
+		t.Squash = true
 	}
 
 	return t
 }
+
+// parseOptions parses the tag options following the name.
+func (t *Tag) parseOptions(v string) {
+	var last *string // the previous options value
+
+	for i, v := range strings.Split(v, ",") {
+		if i == 0 {
+			continue
+		}
+
+		switch v {
+		case omitempty:
+			t.OmitEmpty = true
+			last = nil
+
+			continue
+		case required:
+			t.Required = true
+			last = nil
+
+			continue
+		case squash, inline:
+			t.Squash = true
+			last = nil
+
+			continue
+		}
+
+		name, value := option(v)
+
+		switch name {
+		case optDefault:
+			t.Default = value
+			t.HasDefault = true
+			last = &t.Default
+		case optMin:
+			t.Min = value
+			last = nil
+		case optMax:
+			t.Max = value
+			last = nil
+		case optOneOf:
+			t.OneOf = strings.Fields(value)
+			last = nil
+		case optUnit:
+			t.Unit = value
+			last = nil
+		case optLayout:
+			t.Layout = value
+			last = &t.Layout
+		case optAlias:
+			t.Aliases = append(t.Aliases, strings.Fields(value)...)
+			last = nil
+		case optDepr:
+			t.Deprecated = append(t.Deprecated, strings.Fields(value)...)
+			last = nil
+		default:
+			if last != nil {
+				*last += "," + v
+			}
+		}
+	}
+}
+
+// parseInline parses the options of another decoders tag, only inline, or squash as mapstructure
+// names it, applies.
+func (t *Tag) parseInline(v string) {
+	for _, v := range strings.Split(v, ",")[1:] {
+		if v == squash || v == inline {
+			t.Squash = true
+		}
+	}
+}
+
+// option splits a tag option into its name and value, e.g default=foo returns default and foo.
+func option(v string) (string, string) {
+	elms := strings.SplitN(v, "=", 2)
+	if len(elms) == 1 {
+		return elms[0], ""
+	}
+
+	return elms[0], elms[1]
+}
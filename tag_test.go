@@ -0,0 +1,114 @@
+package gofig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTagFromStructField(t *testing.T) {
+	type Config struct {
+		Untagged  string
+		Gofig     string   `gofig:"gofig_name,required" yaml:"yaml_name"`
+		Fallback  string   `yaml:"yaml_name" json:"json_name"`
+		Unnamed   string   `gofig:",default=1,2" json:"unnamed,omitempty"`
+		Ignored   string   `gofig:"-" yaml:"yaml_name"`
+		IgnoredJS string   `json:"-"`
+		Dash      string   `json:"-,"`
+		Inline    struct{} `yaml:",inline"`
+		Marshaled int      `json:"port,omitempty,string"`
+		Renamed   int      `gofig:"max,alias=maxconns max_conns,deprecated=pool.max,alias=conns"`
+	}
+
+	tags := []string{"gofig", "yaml", "json"}
+
+	cases := map[string]struct {
+		field string
+		tags  []string
+		want  Tag
+	}{
+		"Untagged": {
+			field: "Untagged",
+			tags:  tags,
+			want:  Tag{Name: "Untagged"},
+		},
+		"Default": {
+			field: "Fallback",
+			want:  Tag{Name: "Fallback"},
+		},
+		"First": {
+			field: "Gofig",
+			tags:  tags,
+			want:  Tag{Name: "gofig_name", Named: true, Required: true, RawTag: "gofig_name,required"},
+		},
+		"Custom": {
+			field: "Gofig",
+			tags:  []string{"yaml"},
+			want:  Tag{Name: "yaml_name", Named: true, RawTag: "yaml_name"},
+		},
+		"Fallback": {
+			field: "Fallback",
+			tags:  tags,
+			want:  Tag{Name: "yaml_name", Named: true, RawTag: "yaml_name"},
+		},
+		"FallbackName": {
+			field: "Unnamed",
+			tags:  tags,
+			want:  Tag{Name: "unnamed", Named: true, Default: "1,2", HasDefault: true, RawTag: ",default=1,2"},
+		},
+		"Ignored": {
+			field: "Ignored",
+			tags:  tags,
+			want:  Tag{Name: "Ignored", Ignore: true},
+		},
+		"IgnoredFallback": {
+			field: "IgnoredJS",
+			tags:  tags,
+			want:  Tag{Name: "IgnoredJS", Ignore: true},
+		},
+		"Dash": {
+			field: "Dash",
+			tags:  tags,
+			want:  Tag{Name: "-", Named: true, RawTag: "-,"},
+		},
+		"Inline": {
+			field: "Inline",
+			tags:  tags,
+			want:  Tag{Name: "Inline", Squash: true, RawTag: ",inline"},
+		},
+		"ForeignOptions": {
+			field: "Marshaled",
+			tags:  tags,
+			want:  Tag{Name: "port", Named: true, RawTag: "port,omitempty,string"},
+		},
+		"Aliases": {
+			field: "Renamed",
+			want: Tag{
+				Name:       "max",
+				Named:      true,
+				Aliases:    []string{"maxconns", "max_conns", "conns"},
+				Deprecated: []string{"pool.max"},
+				RawTag:     "max,alias=maxconns max_conns,deprecated=pool.max,alias=conns",
+			},
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f, ok := reflect.TypeOf(Config{}).FieldByName(tc.field)
+			if !ok {
+				t.Fatalf("no field %s", tc.field)
+			}
+
+			got := TagFromStructField(f, tc.tags...)
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package gofig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	durationType    = reflect.TypeOf(time.Duration(0))
+	timeType        = reflect.TypeOf(time.Time{})
+	locationPtrType = reflect.TypeOf((*time.Location)(nil))
+)
+
+// setDuration sets the fields value to a duration. Strings are parsed by time.ParseDuration, e.g
+// 30s, numbers are in the unit given by the unit tag option, e.g unit=s, defaulting to nanoseconds.
+func (d decoder) setDuration(field reflect.Value, value interface{}) error {
+	unit, err := d.unit()
+	if err != nil {
+		return err
+	}
+
+	var n float64
+
+	switch t := value.(type) {
+	case time.Duration:
+		field.SetInt(int64(t))
+
+		return nil
+	case string:
+		v, err := time.ParseDuration(t)
+		if err == nil {
+			field.SetInt(int64(v))
+
+			return nil
+		}
+
+		f, ferr := strconv.ParseFloat(t, 64)
+		if ferr != nil {
+			return err
+		}
+
+		n = f
+	case json.Number:
+		v, err := t.Float64()
+		if err != nil {
+			return err
+		}
+
+		n = v
+	case int, int8, int16, int32, int64:
+		n = float64(reflect.ValueOf(t).Int())
+	case uint, uint8, uint16, uint32, uint64:
+		n = float64(reflect.ValueOf(t).Uint())
+	case float32, float64:
+		n = reflect.ValueOf(t).Float()
+	default:
+		return ErrSetValue{
+			Field: field,
+			Value: reflect.ValueOf(value),
+		}
+	}
+
+	i, ok := floatToInt(n * float64(unit))
+	if !ok {
+		return ErrSetValue{
+			Field: field,
+			Value: reflect.ValueOf(value),
+		}
+	}
+
+	field.SetInt(i)
+
+	return nil
+}
+
+// unit returns the duration of the unit tag option, one of ns, us, ms, s, m or h.
+func (d decoder) unit() (time.Duration, error) {
+	if d.tag.Unit == "" {
+		return time.Nanosecond, nil
+	}
+
+	u, err := time.ParseDuration("1" + d.tag.Unit)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration unit %q", d.tag.Unit)
+	}
+
+	return u, nil
+}
+
+// setTime sets the fields value to a time. Strings are parsed with the layout tag option,
+// defaulting to RFC3339.
+func (d decoder) setTime(field reflect.Value, value interface{}) error {
+	switch t := value.(type) {
+	case time.Time:
+		field.Set(reflect.ValueOf(t))
+	case string:
+		layout := d.tag.Layout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+
+		v, err := time.Parse(layout, t)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(v))
+	default:
+		return ErrSetValue{
+			Field: field,
+			Value: reflect.ValueOf(value),
+		}
+	}
+
+	return nil
+}
+
+// setLocation sets the fields value to the location with the given IANA name, e.g Europe/London.
+func setLocation(field reflect.Value, value interface{}) error {
+	switch t := value.(type) {
+	case *time.Location:
+		field.Set(reflect.ValueOf(t))
+	case string:
+		loc, err := time.LoadLocation(t)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(loc))
+	default:
+		return ErrSetValue{
+			Field: field,
+			Value: reflect.ValueOf(value),
+		}
+	}
+
+	return nil
+}
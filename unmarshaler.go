@@ -2,15 +2,62 @@ package gofig
 
 import "reflect"
 
-// Unmarshaler is an interface implemented by types that can unmarshal a values themselves.
+// Unmarshaler is an interface implemented by types that can unmarshal a values themselves. Structs
+// implementing Unmarshaler are not flattened, they receive the whole map of values below their key.
 type Unmarshaler interface {
 	UnmarshalGoFig(value interface{}) error
 }
 
-// unmarshaler checks to see if the given field implements the Unmarshaler interface.
-// If it does the Unmarshaler is returned, else nil is returned.
+// ContextUnmarshaler is like Unmarshaler but also receives the context the value was set in. The
+// context can decode values into other types, for example to select a concrete type based on a
+// type key within the value.
+type ContextUnmarshaler interface {
+	UnmarshalGoFigContext(ctx UnmarshalContext, value interface{}) error
+}
+
+// An UnmarshalContext describes where a value being unmarshaled came from.
+type UnmarshalContext struct {
+	Key    string // the flattened key, e.g storage.backend
+	Parser Parser // the parser that supplied the value
+
+	decoder decoder
+}
+
+// Decode decodes the value into dst which must be a non-nil pointer. Structs are decoded from maps
+// like the configuration itself, their struct tags, defaults and Validators apply.
+func (c UnmarshalContext) Decode(value interface{}, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return ErrInvalidValue{reflect.TypeOf(dst)}
+	}
+
+	m, ok := toMap(value)
+	if !ok || !c.decoder.isStruct(rv.Elem().Type()) {
+		return c.decoder.set(rv.Elem(), value)
+	}
+
+	l, err := New(dst, c.decoder.opts...)
+	if err != nil {
+		return err
+	}
+
+	p := NewInMemoryParser()
+	for k, v := range m {
+		p.Add(k, v)
+	}
+
+	return l.Parse(p)
+}
+
+var (
+	unmarshalerType        = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	contextUnmarshalerType = reflect.TypeOf((*ContextUnmarshaler)(nil)).Elem()
+)
+
+// unmarshaler checks to see if the given field implements the Unmarshaler or ContextUnmarshaler
+// interface. If it does the implementation is returned, else nil is returned.
 // Lifted from go json stdlib
-func unmarshaler(value reflect.Value) Unmarshaler {
+func unmarshaler(value reflect.Value) interface{} {
 	if value.Kind() != reflect.Ptr && value.Type().Name() != "" && value.CanAddr() {
 		value = value.Addr()
 	}
@@ -42,7 +89,8 @@ func unmarshaler(value reflect.Value) Unmarshaler {
 		}
 
 		if value.Type().NumMethod() > 0 && value.CanInterface() {
-			if u, ok := value.Interface().(Unmarshaler); ok {
+			switch u := value.Interface().(type) {
+			case ContextUnmarshaler, Unmarshaler:
 				return u
 			}
 
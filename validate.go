@@ -0,0 +1,174 @@
+package gofig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// A Validator is implemented by structs and fields that can validate their own values. Validate is
+// called after every Parse, including those triggered by Notify.
+type Validator interface {
+	Validate() error
+}
+
+// structValidator is a struct implementing Validator and its flattened key.
+type structValidator struct {
+	key   string
+	value reflect.Value
+}
+
+var validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+
+// addValidator adds the value to the list of validators if it implements the Validator interface.
+func (l *Loader) addValidator(rv reflect.Value, key string) {
+	if rv.Type().Implements(validatorType) || reflect.PtrTo(rv.Type()).Implements(validatorType) {
+		l.validators = append(l.validators, structValidator{
+			key:   key,
+			value: rv,
+		})
+	}
+}
+
+// validate validates the fields tag rules and then any Validator implementations, returning every
+// validation error.
+func (l *Loader) validate() error {
+	var errs MultiError
+
+	keys := make([]string, 0, len(l.fields))
+	for key := range l.fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := l.fields[key]
+
+		if !field.IsSet() {
+			continue
+		}
+
+		// Struct pointers are validated with the other structs
+		_, isStructPtr := field.(*pointerField)
+
+		err := validateTag(field.Value(), field.Tag())
+		if err == nil && !isStructPtr {
+			if v := validator(field.Value()); v != nil {
+				err = v.Validate()
+			}
+		}
+
+		if err != nil {
+			errs.Add(ErrValidation{
+				Key:    key,
+				Parser: unwrap(field.Parser()),
+				Err:    err,
+			})
+		}
+	}
+
+	for _, sv := range l.validators {
+		// Nil struct pointers are not validated
+		v := validator(sv.value)
+		if v == nil {
+			continue
+		}
+
+		if err := v.Validate(); err != nil {
+			errs.Add(ErrValidation{
+				Key: sv.key,
+				Err: err,
+			})
+		}
+	}
+
+	return errs.NilOrError()
+}
+
+// validator returns the values Validator implementation, or that of its pointer, else nil.
+func validator(rv reflect.Value) Validator {
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() {
+		rv = rv.Addr()
+	}
+
+	if !rv.CanInterface() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return nil
+	}
+
+	if v, ok := rv.Interface().(Validator); ok {
+		return v
+	}
+
+	return nil
+}
+
+// validateTag validates a value against the min, max and oneof tag rules. For strings, slices and
+// maps min and max validate the length.
+func validateTag(rv reflect.Value, tag Tag) error {
+	if tag.Min == "" && tag.Max == "" && len(tag.OneOf) == 0 {
+		return nil
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	var (
+		n    float64
+		what = "value"
+	)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n = float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = rv.Float()
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		n = float64(rv.Len())
+		what = "length"
+	}
+
+	if tag.Min != "" {
+		min, err := strconv.ParseFloat(tag.Min, 64)
+		if err != nil {
+			return err
+		}
+
+		if n < min {
+			return fmt.Errorf("%s must be at least %s", what, tag.Min)
+		}
+	}
+
+	if tag.Max != "" {
+		max, err := strconv.ParseFloat(tag.Max, 64)
+		if err != nil {
+			return err
+		}
+
+		if n > max {
+			return fmt.Errorf("%s must be at most %s", what, tag.Max)
+		}
+	}
+
+	if len(tag.OneOf) > 0 {
+		v := fmt.Sprint(rv.Interface())
+
+		for _, o := range tag.OneOf {
+			if v == o {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("value %s must be one of %v", v, tag.OneOf)
+	}
+
+	return nil
+}
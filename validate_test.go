@@ -0,0 +1,117 @@
+package gofig
+
+import (
+	"errors"
+	"testing"
+)
+
+type pool struct {
+	Min int `gofig:"min"`
+	Max int `gofig:"max,max=100"`
+}
+
+func (p pool) Validate() error {
+	if p.Min > p.Max {
+		return errors.New("min must not exceed max")
+	}
+
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	type Config struct {
+		Name  string `gofig:"name,min=3"`
+		Level string `gofig:"level,oneof=debug info error"`
+		DB    struct {
+			Pool pool `gofig:"pool"`
+		} `gofig:"db"`
+	}
+
+	cases := map[string]struct {
+		values map[string]interface{}
+		key    string
+		parser bool
+	}{
+		"Valid": {
+			values: map[string]interface{}{
+				"name":        "foo",
+				"level":       "info",
+				"db.pool.min": 1,
+				"db.pool.max": 10,
+			},
+		},
+		"Min": {
+			values: map[string]interface{}{
+				"name": "fo",
+			},
+			key:    "name",
+			parser: true,
+		},
+		"Max": {
+			values: map[string]interface{}{
+				"db.pool.max": 101,
+			},
+			key:    "db.pool.max",
+			parser: true,
+		},
+		"OneOf": {
+			values: map[string]interface{}{
+				"level": "warn",
+			},
+			key:    "level",
+			parser: true,
+		},
+		"Validator": {
+			values: map[string]interface{}{
+				"db.pool.min": 10,
+				"db.pool.max": 1,
+			},
+			key: "db.pool",
+		},
+	}
+
+	for name, testCase := range cases {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+
+			g, err := New(&cfg, SetLogger(NopLogger()))
+			if err != nil {
+				t.Fatal("want nil error, got:", err)
+			}
+
+			p := NewInMemoryParser()
+			for k, v := range tc.values {
+				p.Add(k, v)
+			}
+
+			err = g.Parse(p)
+
+			if tc.key == "" {
+				if err != nil {
+					t.Fatal("want nil error, got:", err)
+				}
+
+				return
+			}
+
+			var verr ErrValidation
+			if !errors.As(err, &verr) {
+				t.Fatalf("want ErrValidation, got: %v", err)
+			}
+
+			t.Log(err)
+
+			if verr.Key != tc.key {
+				t.Errorf("want key %s, got %s", tc.key, verr.Key)
+			}
+
+			if tc.parser && verr.Parser != p {
+				t.Errorf("want parser %v, got %v", p, verr.Parser)
+			}
+		})
+	}
+}